@@ -0,0 +1,375 @@
+// Package auth issues and verifies the session tokens clients send with
+// every call.
+//
+// A token is JWT-style: base64url encoded JSON claims, a dot, and the
+// base64url encoded HMAC-SHA256 of the claims. Tokens are verified without
+// any lookup, so they stay valid across restarts as long as the key does.
+// Revocations are kept until the revoked tokens expire, and can be saved to
+// a file so revoked tokens stay revoked across restarts too.
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maria-mz/bash-battle-server/clock"
+	"github.com/maria-mz/bash-battle-server/log"
+)
+
+var ErrInvalidToken = errors.New("token is invalid")
+var ErrTokenExpired = errors.New("token has expired")
+var ErrTokenRevoked = errors.New("token has been revoked")
+
+// KeySize is the size, in bytes, of generated keys.
+const KeySize = 32
+
+// Claims are what a token says about its holder.
+type Claims struct {
+	ID        string // Unique to the token
+	SessionID string // Shared by the tokens refreshed from one another
+	Username  string
+	GameID    string // Game the client was in when the token was issued
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// payload is how claims are encoded in a token.
+type payload struct {
+	ID        string `json:"jti"`
+	SessionID string `json:"sid"`
+	Username  string `json:"sub"`
+	GameID    string `json:"gid,omitempty"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// NewKey returns a random key, for when none is configured.
+func NewKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Issuer issues tokens signed with its key, valid for its TTL. It is safe
+// for concurrent use.
+type Issuer struct {
+	key   []byte
+	ttl   time.Duration
+	clock clock.Clock
+
+	revoked     map[string]time.Time // Token ID -> when the token expires
+	revokedFile string               // Where revoked is saved, if anywhere
+	mu          sync.Mutex
+
+	// Held while revokedFile is written, without mu, so verifying tokens
+	// doesn't wait on the disk. Taken before mu when both are needed
+	fileMu sync.Mutex
+}
+
+// revocation is how a revoked token is saved, one per line.
+type revocation struct {
+	ID        string `json:"jti"`
+	ExpiresAt int64  `json:"exp"` // Seconds
+}
+
+func NewIssuer(key []byte, ttl time.Duration, clk clock.Clock) *Issuer {
+	return &Issuer{
+		key:     key,
+		ttl:     ttl,
+		clock:   clk,
+		revoked: make(map[string]time.Time),
+	}
+}
+
+// Issue returns a new token for the session.
+func (issuer *Issuer) Issue(sessionID string, username string, gameID string) (string, Claims, error) {
+	now := issuer.clock.Now().UTC().Truncate(time.Second) // Tokens hold seconds
+
+	claims := Claims{
+		ID:        uuid.New().String(),
+		SessionID: sessionID,
+		Username:  username,
+		GameID:    gameID,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(issuer.ttl),
+	}
+
+	data, err := json.Marshal(payload{
+		ID:        claims.ID,
+		SessionID: claims.SessionID,
+		Username:  claims.Username,
+		GameID:    claims.GameID,
+		IssuedAt:  claims.IssuedAt.Unix(),
+		ExpiresAt: claims.ExpiresAt.Unix(),
+	})
+
+	if err != nil {
+		return "", Claims{}, err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+	token := encoded + "." + base64.RawURLEncoding.EncodeToString(issuer.sign(encoded))
+
+	return token, claims, nil
+}
+
+// Verify checks the token's signature, expiry and revocation, and returns
+// its claims.
+func (issuer *Issuer) Verify(token string) (Claims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+
+	if !ok {
+		return Claims{}, ErrInvalidToken
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+
+	if err != nil || !hmac.Equal(mac, issuer.sign(encoded)) {
+		return Claims{}, ErrInvalidToken
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+
+	var p payload
+
+	if err := json.Unmarshal(data, &p); err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+
+	claims := Claims{
+		ID:        p.ID,
+		SessionID: p.SessionID,
+		Username:  p.Username,
+		GameID:    p.GameID,
+		IssuedAt:  time.Unix(p.IssuedAt, 0).UTC(),
+		ExpiresAt: time.Unix(p.ExpiresAt, 0).UTC(),
+	}
+
+	if !issuer.clock.Now().Before(claims.ExpiresAt) {
+		return Claims{}, ErrTokenExpired
+	}
+
+	if issuer.IsRevoked(claims.ID) {
+		return Claims{}, ErrTokenRevoked
+	}
+
+	return claims, nil
+}
+
+// Refresh returns a new token for the session of a valid token, with a
+// new expiry and the given game ID. The old token is revoked.
+func (issuer *Issuer) Refresh(token string, gameID string) (string, Claims, error) {
+	claims, err := issuer.Verify(token)
+
+	if err != nil {
+		return "", Claims{}, err
+	}
+
+	// A token can only be refreshed once, even by concurrent calls
+	if !issuer.Revoke(claims) {
+		return "", Claims{}, ErrTokenRevoked
+	}
+
+	return issuer.Issue(claims.SessionID, claims.Username, gameID)
+}
+
+// Revoke makes the token with the claims fail verification from now on.
+// Returns false if it was already revoked.
+func (issuer *Issuer) Revoke(claims Claims) bool {
+	path, ok := issuer.revoke(claims)
+
+	if !ok {
+		return false
+	}
+
+	if path != "" {
+		issuer.fileMu.Lock()
+		defer issuer.fileMu.Unlock()
+
+		if err := appendRevoked(path, claims.ID, claims.ExpiresAt); err != nil {
+			log.Logger.Error("Failed to save revoked token", "path", path, "err", err)
+		}
+	}
+
+	return true
+}
+
+// revoke adds the revocation, and returns the file to save it in.
+func (issuer *Issuer) revoke(claims Claims) (string, bool) {
+	issuer.mu.Lock()
+	defer issuer.mu.Unlock()
+
+	if _, ok := issuer.revoked[claims.ID]; ok {
+		return "", false
+	}
+
+	issuer.revoked[claims.ID] = claims.ExpiresAt
+
+	return issuer.revokedFile, true
+}
+
+func (issuer *Issuer) IsRevoked(id string) bool {
+	issuer.mu.Lock()
+	defer issuer.mu.Unlock()
+
+	_, ok := issuer.revoked[id]
+	return ok
+}
+
+// Cleanup forgets the revoked tokens that have expired since, they fail
+// verification anyway. Their file is rewritten without them.
+func (issuer *Issuer) Cleanup() {
+	// Revocations made meanwhile are appended once the file is rewritten
+	issuer.fileMu.Lock()
+	defer issuer.fileMu.Unlock()
+
+	revoked, path, expired := issuer.forgetExpired(issuer.clock.Now())
+
+	if !expired || path == "" {
+		return
+	}
+
+	if err := writeRevoked(path, revoked); err != nil {
+		log.Logger.Error("Failed to save revoked tokens", "path", path, "err", err)
+	}
+}
+
+// forgetExpired drops the revocations expired by now. Returns a copy of
+// the ones left, the file they are saved in, and whether any expired.
+func (issuer *Issuer) forgetExpired(now time.Time) (map[string]time.Time, string, bool) {
+	issuer.mu.Lock()
+	defer issuer.mu.Unlock()
+
+	expired := false
+	revoked := make(map[string]time.Time, len(issuer.revoked))
+
+	for id, expiresAt := range issuer.revoked {
+		if now.Before(expiresAt) {
+			revoked[id] = expiresAt
+		} else {
+			delete(issuer.revoked, id)
+			expired = true
+		}
+	}
+
+	return revoked, issuer.revokedFile, expired
+}
+
+// PersistRevocations loads the revocations saved in the file at path, if
+// it exists, and saves every change to them there from now on.
+func (issuer *Issuer) PersistRevocations(path string) error {
+	issuer.mu.Lock()
+	defer issuer.mu.Unlock()
+
+	data, err := os.ReadFile(path)
+
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if err == nil {
+		now := issuer.clock.Now()
+
+		for _, line := range strings.Split(string(data), "\n") {
+			if line == "" {
+				continue
+			}
+
+			var saved revocation
+
+			if err := json.Unmarshal([]byte(line), &saved); err != nil {
+				return err
+			}
+
+			if t := time.Unix(saved.ExpiresAt, 0).UTC(); now.Before(t) {
+				issuer.revoked[saved.ID] = t
+			}
+		}
+	}
+
+	issuer.revokedFile = path
+
+	return nil
+}
+
+// appendRevoked adds a revocation to the end of the file at path. Must be
+// called with fileMu held.
+func appendRevoked(path string, id string, expiresAt time.Time) error {
+	line, err := json.Marshal(revocation{ID: id, ExpiresAt: expiresAt.Unix()})
+
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// writeRevoked replaces the file at path with the revocations, at once so
+// a crash can't leave it half written. Must be called with fileMu held.
+func writeRevoked(path string, revoked map[string]time.Time) error {
+	var data []byte
+
+	for id, expiresAt := range revoked {
+		line, err := json.Marshal(revocation{ID: id, ExpiresAt: expiresAt.Unix()})
+
+		if err != nil {
+			return err
+		}
+
+		data = append(append(data, line...), '\n')
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".revoked-*")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(file.Name()) // Fails once renamed
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+func (issuer *Issuer) sign(encoded string) []byte {
+	mac := hmac.New(sha256.New, issuer.key)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
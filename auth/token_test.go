@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maria-mz/bash-battle-server/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+var testStart = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestIssuer() (*Issuer, *clock.Fake) {
+	clk := clock.NewFake(testStart)
+	return NewIssuer([]byte("test-key-test-key-test-key-test!"), time.Hour, clk), clk
+}
+
+func TestIssueAndVerify(t *testing.T) {
+	issuer, _ := newTestIssuer()
+
+	token, issued, err := issuer.Issue("session-1", "player-1", "ABCDEF")
+	assert.Nil(t, err)
+
+	claims, err := issuer.Verify(token)
+
+	assert.Nil(t, err)
+	assert.Equal(t, issued, claims)
+	assert.Equal(t, "session-1", claims.SessionID)
+	assert.Equal(t, "player-1", claims.Username)
+	assert.Equal(t, "ABCDEF", claims.GameID)
+	assert.True(t, claims.ExpiresAt.Equal(testStart.Add(time.Hour)))
+}
+
+func TestVerify_Invalid(t *testing.T) {
+	issuer, _ := newTestIssuer()
+
+	token, _, _ := issuer.Issue("session-1", "player-1", "")
+	encoded, signature, _ := strings.Cut(token, ".")
+
+	other := NewIssuer([]byte("another-key"), time.Hour, clock.NewFake(testStart))
+	otherToken, _, _ := other.Issue("session-1", "admin", "")
+	otherEncoded, _, _ := strings.Cut(otherToken, ".")
+
+	invalid := []string{
+		"",
+		"not-a-token",
+		encoded,
+		encoded + ".",
+		encoded + "." + signature + "x",
+		otherToken,                     // Signed with another key
+		otherEncoded + "." + signature, // Claims swapped
+	}
+
+	for _, token := range invalid {
+		_, err := issuer.Verify(token)
+		assert.ErrorIs(t, err, ErrInvalidToken, token)
+	}
+}
+
+func TestVerify_Expired(t *testing.T) {
+	issuer, clk := newTestIssuer()
+
+	token, _, _ := issuer.Issue("session-1", "player-1", "")
+
+	clk.Advance(time.Hour - time.Second)
+	_, err := issuer.Verify(token)
+	assert.Nil(t, err)
+
+	clk.Advance(time.Second)
+	_, err = issuer.Verify(token)
+	assert.ErrorIs(t, err, ErrTokenExpired)
+}
+
+func TestRefresh(t *testing.T) {
+	issuer, clk := newTestIssuer()
+
+	token, old, _ := issuer.Issue("session-1", "player-1", "")
+
+	clk.Advance(30 * time.Minute)
+
+	refreshed, claims, err := issuer.Refresh(token, "ABCDEF")
+
+	assert.Nil(t, err)
+	assert.Equal(t, old.SessionID, claims.SessionID)
+	assert.NotEqual(t, old.ID, claims.ID)
+	assert.Equal(t, "ABCDEF", claims.GameID)
+	assert.True(t, claims.ExpiresAt.Equal(testStart.Add(90*time.Minute)))
+
+	// The old token can't be used, or refreshed again
+	_, err = issuer.Verify(token)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+
+	_, _, err = issuer.Refresh(token, "")
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+
+	_, err = issuer.Verify(refreshed)
+	assert.Nil(t, err)
+}
+
+func TestRevokeAndCleanup(t *testing.T) {
+	issuer, clk := newTestIssuer()
+
+	token, claims, _ := issuer.Issue("session-1", "player-1", "")
+	issuer.Revoke(claims)
+
+	_, err := issuer.Verify(token)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+
+	issuer.Cleanup()
+	assert.True(t, issuer.IsRevoked(claims.ID))
+
+	// Expired tokens don't need to be remembered
+	clk.Advance(time.Hour)
+	issuer.Cleanup()
+	assert.False(t, issuer.IsRevoked(claims.ID))
+
+	_, err = issuer.Verify(token)
+	assert.ErrorIs(t, err, ErrTokenExpired)
+}
+
+func TestPersistRevocations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "revoked.json")
+	issuer, clk := newTestIssuer()
+
+	assert.Nil(t, issuer.PersistRevocations(path)) // Not there yet
+
+	token, claims, _ := issuer.Issue("session-1", "player-1", "")
+	issuer.Revoke(claims)
+
+	// As after a restart
+	restarted := NewIssuer([]byte("test-key-test-key-test-key-test!"), time.Hour, clk)
+	assert.Nil(t, restarted.PersistRevocations(path))
+
+	_, err := restarted.Verify(token)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+
+	// Expired revocations are dropped from the file too
+	clk.Advance(time.Hour)
+	restarted.Cleanup()
+
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Empty(t, string(data))
+
+	assert.NotNil(t, restarted.PersistRevocations(writeFile(t, "not json")))
+}
+
+func TestPersistRevocations_AppendsUntilCleanup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "revoked.json")
+	issuer, clk := newTestIssuer()
+
+	assert.Nil(t, issuer.PersistRevocations(path))
+
+	_, first, _ := issuer.Issue("session-1", "player-1", "")
+	issuer.Revoke(first)
+
+	clk.Advance(30 * time.Minute)
+
+	_, second, _ := issuer.Issue("session-2", "player-2", "")
+	issuer.Revoke(second)
+
+	// One line per revocation, the file isn't rewritten
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], first.ID)
+	assert.Contains(t, lines[1], second.ID)
+
+	// Only the first has expired, and is compacted away
+	clk.Advance(45 * time.Minute)
+	issuer.Cleanup()
+
+	data, _ = os.ReadFile(path)
+	lines = strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 1)
+	assert.Contains(t, lines[0], second.ID)
+
+	restarted := NewIssuer([]byte("test-key-test-key-test-key-test!"), time.Hour, clk)
+	assert.Nil(t, restarted.PersistRevocations(path))
+	assert.True(t, restarted.IsRevoked(second.ID))
+	assert.False(t, restarted.IsRevoked(first.ID))
+}
+
+func writeFile(t *testing.T, data string) string {
+	path := filepath.Join(t.TempDir(), "revoked.json")
+	assert.Nil(t, os.WriteFile(path, []byte(data), 0o600))
+	return path
+}
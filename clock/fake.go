@@ -0,0 +1,179 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake is a Clock whose time only moves when Advance is called. Timers,
+// tickers and AfterFunc calls fire as Advance moves past them, in order.
+// It is safe for concurrent use.
+type Fake struct {
+	now     time.Time
+	waiters []*fakeTimer
+	mu      sync.Mutex
+	cond    *sync.Cond
+}
+
+// NewFake returns a Fake clock starting at now.
+func NewFake(now time.Time) *Fake {
+	fake := &Fake{now: now}
+	fake.cond = sync.NewCond(&fake.mu)
+	return fake
+}
+
+func (fake *Fake) Now() time.Time {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	return fake.now
+}
+
+func (fake *Fake) Since(t time.Time) time.Duration {
+	return fake.Now().Sub(t)
+}
+
+func (fake *Fake) Until(t time.Time) time.Duration {
+	return t.Sub(fake.Now())
+}
+
+func (fake *Fake) NewTimer(d time.Duration) Timer {
+	return fake.addWaiter(d, 0, nil)
+}
+
+func (fake *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	return fakeTicker{fake.addWaiter(d, d, nil)}
+}
+
+func (fake *Fake) AfterFunc(d time.Duration, f func()) Timer {
+	return fake.addWaiter(d, 0, f)
+}
+
+// Advance moves the time forward by d, firing everything due on the way.
+func (fake *Fake) Advance(d time.Duration) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	end := fake.now.Add(d)
+
+	for len(fake.waiters) > 0 && !fake.waiters[0].when.After(end) {
+		waiter := fake.waiters[0]
+		fake.now = waiter.when
+		waiter.fire()
+
+		if waiter.period > 0 {
+			waiter.when = waiter.when.Add(waiter.period)
+		} else {
+			fake.removeWaiter(waiter)
+		}
+
+		fake.sortWaiters()
+	}
+
+	fake.now = end
+}
+
+// BlockUntil blocks until at least n timers or tickers are waiting to
+// fire, so a test can Advance once the code under test is waiting.
+func (fake *Fake) BlockUntil(n int) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	for len(fake.waiters) < n {
+		fake.cond.Wait()
+	}
+}
+
+// Waiters returns how many timers and tickers are waiting to fire.
+func (fake *Fake) Waiters() int {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	return len(fake.waiters)
+}
+
+func (fake *Fake) addWaiter(d time.Duration, period time.Duration, f func()) *fakeTimer {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	waiter := &fakeTimer{
+		clock:  fake,
+		when:   fake.now.Add(d),
+		period: period,
+		f:      f,
+		ch:     make(chan time.Time, 1),
+	}
+
+	if d <= 0 && period == 0 {
+		waiter.fire()
+		return waiter
+	}
+
+	fake.waiters = append(fake.waiters, waiter)
+	fake.sortWaiters()
+	fake.cond.Broadcast()
+
+	return waiter
+}
+
+func (fake *Fake) removeWaiter(waiter *fakeTimer) bool {
+	for i, w := range fake.waiters {
+		if w == waiter {
+			fake.waiters = append(fake.waiters[:i], fake.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (fake *Fake) sortWaiters() {
+	sort.SliceStable(fake.waiters, func(i, j int) bool {
+		return fake.waiters[i].when.Before(fake.waiters[j].when)
+	})
+}
+
+// fakeTimer is a Timer or Ticker of a Fake clock.
+type fakeTimer struct {
+	clock  *Fake
+	when   time.Time
+	period time.Duration // Set for tickers
+	f      func()        // Set for AfterFunc
+	ch     chan time.Time
+}
+
+// fire is called with the clock's lock held. Like time.Ticker, a tick is
+// dropped if the last one wasn't received yet.
+func (t *fakeTimer) fire() {
+	if t.f != nil {
+		go t.f()
+		return
+	}
+
+	select {
+	case t.ch <- t.when:
+	default:
+	}
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	return t.clock.removeWaiter(t)
+}
+
+type fakeTicker struct {
+	*fakeTimer
+}
+
+func (t fakeTicker) Stop() {
+	t.fakeTimer.Stop()
+}
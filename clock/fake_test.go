@@ -0,0 +1,86 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testStart = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func fired(ch <-chan time.Time) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestFake_Timer(t *testing.T) {
+	clk := NewFake(testStart)
+	timer := clk.NewTimer(time.Second)
+
+	assert.Equal(t, 1, clk.Waiters())
+
+	clk.Advance(999 * time.Millisecond)
+	assert.False(t, fired(timer.C()))
+
+	clk.Advance(time.Millisecond)
+	assert.True(t, fired(timer.C()))
+	assert.Equal(t, testStart.Add(time.Second), clk.Now())
+	assert.Equal(t, 0, clk.Waiters())
+	assert.False(t, timer.Stop())
+}
+
+func TestFake_TimerStop(t *testing.T) {
+	clk := NewFake(testStart)
+	timer := clk.NewTimer(time.Second)
+
+	assert.True(t, timer.Stop())
+
+	clk.Advance(time.Hour)
+	assert.False(t, fired(timer.C()))
+}
+
+func TestFake_Ticker(t *testing.T) {
+	clk := NewFake(testStart)
+	ticker := clk.NewTicker(time.Second)
+
+	for i := 0; i < 3; i++ {
+		clk.Advance(time.Second)
+		assert.True(t, fired(ticker.C()))
+	}
+
+	ticker.Stop()
+	clk.Advance(time.Second)
+
+	assert.False(t, fired(ticker.C()))
+	assert.Equal(t, 0, clk.Waiters())
+}
+
+func TestFake_AfterFunc(t *testing.T) {
+	clk := NewFake(testStart)
+	done := make(chan time.Time)
+
+	clk.AfterFunc(time.Minute, func() { done <- clk.Now() })
+	clk.Advance(2 * time.Minute)
+
+	// Runs on its own goroutine, after the clock moved all the way
+	assert.Equal(t, testStart.Add(2*time.Minute), <-done)
+}
+
+func TestFake_BlockUntil(t *testing.T) {
+	clk := NewFake(testStart)
+	timer := make(chan Timer)
+
+	go func() {
+		timer <- clk.NewTimer(time.Second)
+	}()
+
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+
+	assert.True(t, fired((<-timer).C()))
+}
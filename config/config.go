@@ -2,52 +2,201 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/maria-mz/bash-battle-proto/proto"
+	"gopkg.in/yaml.v3"
 )
 
+var ErrUnknownFormat = errors.New("unknown config file format, expected .json, .yaml, .yml or .toml")
+
 type GameConfig struct {
-	MaxPlayers        int
-	Rounds            int
-	RoundDuration     int
-	CountdownDuration int
-	Difficulty        int
-	FileSize          int
+	MaxPlayers         int    `json:"maxPlayers" yaml:"maxPlayers" toml:"maxPlayers"`
+	Rounds             int    `json:"rounds" yaml:"rounds" toml:"rounds"`
+	RoundDuration      int    `json:"roundDuration" yaml:"roundDuration" toml:"roundDuration"`
+	CountdownDuration  int    `json:"countdownDuration" yaml:"countdownDuration" toml:"countdownDuration"`
+	Difficulty         int    `json:"difficulty" yaml:"difficulty" toml:"difficulty"`
+	FileSize           int    `json:"fileSize" yaml:"fileSize" toml:"fileSize"`
+	Scoring            string `json:"scoring" yaml:"scoring" toml:"scoring"`
+	AckTimeout         int    `json:"ackTimeout" yaml:"ackTimeout" toml:"ackTimeout"`                         // Seconds to wait for clients to ack a round event
+	SubmissionDuration int    `json:"submissionDuration" yaml:"submissionDuration" toml:"submissionDuration"` // Seconds players have to submit once a round ends
+}
+
+// DefaultSubmissionDuration is used when GameConfig.SubmissionDuration
+// isn't set.
+const DefaultSubmissionDuration = 10 * time.Second
+
+// DefaultAckTimeout is used when GameConfig.AckTimeout isn't set.
+const DefaultAckTimeout = 5 * time.Second
+
+func (config *GameConfig) GetAckTimeout() time.Duration {
+	if config.AckTimeout <= 0 {
+		return DefaultAckTimeout
+	}
+	return time.Duration(config.AckTimeout) * time.Second
+}
+
+func (config *GameConfig) GetSubmissionDuration() time.Duration {
+	if config.SubmissionDuration <= 0 {
+		return DefaultSubmissionDuration
+	}
+	return time.Duration(config.SubmissionDuration) * time.Second
 }
 
 func (config *GameConfig) ToProto() *proto.GameConfig {
+	maxPlayers := int32(config.MaxPlayers)
+	rounds := int32(config.Rounds)
+	roundSeconds := int32(config.RoundDuration)
+
 	return &proto.GameConfig{
-		MaxPlayers:   int32(config.MaxPlayers),
-		Rounds:       int32(config.Rounds),
-		RoundSeconds: int32(config.RoundDuration),
-		Difficulty:   proto.Difficulty(config.Difficulty),
-		FileSize:     proto.FileSize(config.FileSize),
+		MaxPlayers:   &maxPlayers,
+		Rounds:       &rounds,
+		RoundSeconds: &roundSeconds,
+		Difficulty:   proto.Difficulty(config.Difficulty).Enum(),
+		FileSize:     proto.FileSize(config.FileSize).Enum(),
 	}
 }
 
+// Override returns a copy of the config, with the fields set in the proto
+// config taking precedence, zero values included. Fields the proto doesn't
+// carry, or leaves unset, are kept.
+func (config *GameConfig) Override(protoConfig *proto.GameConfig) GameConfig {
+	merged := *config
+
+	if protoConfig == nil {
+		return merged
+	}
+
+	if protoConfig.MaxPlayers != nil {
+		merged.MaxPlayers = int(protoConfig.GetMaxPlayers())
+	}
+	if protoConfig.Rounds != nil {
+		merged.Rounds = int(protoConfig.GetRounds())
+	}
+	if protoConfig.RoundSeconds != nil {
+		merged.RoundDuration = int(protoConfig.GetRoundSeconds())
+	}
+	if protoConfig.Difficulty != nil {
+		merged.Difficulty = int(protoConfig.GetDifficulty())
+	}
+	if protoConfig.FileSize != nil {
+		merged.FileSize = int(protoConfig.GetFileSize())
+	}
+
+	return merged
+}
+
+// StreamConfig sets how events wait to be sent to each client.
+type StreamConfig struct {
+	QueueSize  int    `json:"queueSize" yaml:"queueSize" toml:"queueSize"`    // Events a client can fall behind by
+	OnOverflow string `json:"onOverflow" yaml:"onOverflow" toml:"onOverflow"` // "dropOldest" or "disconnect"
+}
+
+// TLSConfig sets up TLS for the gRPC service. Certificate files are
+// reloaded when they change, without a restart.
+type TLSConfig struct {
+	Enabled  bool   `json:"enabled" yaml:"enabled" toml:"enabled"`
+	CertFile string `json:"certFile" yaml:"certFile" toml:"certFile"`
+	KeyFile  string `json:"keyFile" yaml:"keyFile" toml:"keyFile"`
+
+	// Generate a self-signed certificate into CertFile and KeyFile if they
+	// don't exist, for local development
+	SelfSigned bool `json:"selfSigned" yaml:"selfSigned" toml:"selfSigned"`
+
+	// CA bundle to verify client certificates against. If set, clients
+	// presenting a certificate must present a valid one
+	ClientCAFile      string `json:"clientCAFile" yaml:"clientCAFile" toml:"clientCAFile"`
+	RequireClientCert bool   `json:"requireClientCert" yaml:"requireClientCert" toml:"requireClientCert"` // Mutual TLS
+}
+
+// AuthConfig sets up the signed session tokens clients authenticate with.
+type AuthConfig struct {
+	// Key tokens are signed with, at least MinSecretLength bytes. Tokens
+	// only stay valid across restarts if it is set, otherwise a random key
+	// is generated on startup
+	Secret   string `json:"secret" yaml:"secret" toml:"secret"`
+	TokenTTL int    `json:"tokenTTL" yaml:"tokenTTL" toml:"tokenTTL"` // Seconds a token is valid for, unless refreshed
+
+	// File the revoked tokens are saved in, so they stay revoked across
+	// restarts. Only used with a Secret, tokens don't survive otherwise
+	RevokedFile string `json:"revokedFile" yaml:"revokedFile" toml:"revokedFile"`
+}
+
+func (config *AuthConfig) GetTokenTTL() time.Duration {
+	return time.Duration(config.TokenTTL) * time.Second
+}
+
+// UsernameConfig sets the rules player names must follow, see the username
+// package.
+type UsernameConfig struct {
+	MinLength int      `json:"minLength" yaml:"minLength" toml:"minLength"`
+	MaxLength int      `json:"maxLength" yaml:"maxLength" toml:"maxLength"`
+	Blocklist []string `json:"blocklist" yaml:"blocklist" toml:"blocklist"` // Words names can't contain, look-alikes included
+}
+
 type Config struct {
-	Host       string     `json:"host"`
-	Port       uint16     `json:"port"`
-	GameConfig GameConfig `json:"gameConfig"`
+	Host          string         `json:"host" yaml:"host" toml:"host"`
+	Port          uint16         `json:"port" yaml:"port" toml:"port"`
+	ChallengesDir string         `json:"challengesDir" yaml:"challengesDir" toml:"challengesDir"`
+	Stream        StreamConfig   `json:"stream" yaml:"stream" toml:"stream"`
+	GameConfig    GameConfig     `json:"gameConfig" yaml:"gameConfig" toml:"gameConfig"`
+	TLS           TLSConfig      `json:"tls" yaml:"tls" toml:"tls"`
+	Auth          AuthConfig     `json:"auth" yaml:"auth" toml:"auth"`
+	Usernames     UsernameConfig `json:"usernames" yaml:"usernames" toml:"usernames"`
+	LogLevel      string         `json:"logLevel" yaml:"logLevel" toml:"logLevel"` // debug, info, warn, error or fatal
 }
 
-func LoadConfig() (Config, error) {
+// DefaultPath is where the config file is read from, unless the -config
+// flag says otherwise.
+const DefaultPath = "config/config.json"
+
+// LoadConfig reads the config file at path. The format is picked by the
+// file extension: .json, .yaml / .yml or .toml.
+func LoadConfig(path string) (Config, error) {
 	var config Config
 
-	file, err := os.Open("config/config.json")
+	data, err := os.ReadFile(path)
 
 	if err != nil {
 		return config, err
 	}
-	defer file.Close()
 
-	decoder := json.NewDecoder(file)
-	err = decoder.Decode(&config)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &config)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &config)
+	case ".toml":
+		err = toml.Unmarshal(data, &config)
+	default:
+		return config, fmt.Errorf("%w: %q", ErrUnknownFormat, path)
+	}
 
 	if err != nil {
-		return config, err
+		return config, fmt.Errorf("%s: %w", path, err)
 	}
 
 	return config, nil
 }
+
+// String formats the config as indented JSON, for printing at startup.
+// The auth secret is redacted.
+func (config Config) String() string {
+	if config.Auth.Secret != "" {
+		config.Auth.Secret = "REDACTED"
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(data)
+}
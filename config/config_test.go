@@ -0,0 +1,301 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/maria-mz/bash-battle-proto/proto"
+	"github.com/maria-mz/bash-battle-server/clock"
+	"github.com/maria-mz/bash-battle-server/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	log.InitLogger()
+	m.Run()
+}
+
+func writeFile(t *testing.T, name string, data string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.Nil(t, os.WriteFile(path, []byte(data), 0o644))
+	return path
+}
+
+func TestLoadConfig_Formats(t *testing.T) {
+	files := map[string]string{
+		"config.json": `{"host": "localhost", "port": 9000, "gameConfig": {"maxPlayers": 4, "scoring": "winloss"}}`,
+		"config.yaml": "host: localhost\nport: 9000\ngameConfig:\n  maxPlayers: 4\n  scoring: winloss\n",
+		"config.yml":  "host: localhost\nport: 9000\ngameConfig:\n  maxPlayers: 4\n  scoring: winloss\n",
+		"config.toml": "host = \"localhost\"\nport = 9000\n[gameConfig]\nmaxPlayers = 4\nscoring = \"winloss\"\n",
+	}
+
+	for name, data := range files {
+		config, err := LoadConfig(writeFile(t, name, data))
+
+		assert.Nil(t, err, name)
+		assert.Equal(t, "localhost", config.Host, name)
+		assert.Equal(t, uint16(9000), config.Port, name)
+		assert.Equal(t, 4, config.GameConfig.MaxPlayers, name)
+		assert.Equal(t, "winloss", config.GameConfig.Scoring, name)
+	}
+}
+
+func TestLoadConfig_UnknownFormat(t *testing.T) {
+	_, err := LoadConfig(writeFile(t, "config.ini", "host=localhost"))
+
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestLoadConfig_ShippedConfig(t *testing.T) {
+	_, err := LoadConfig("config.json")
+
+	assert.Nil(t, err)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeFile(t, "config.yaml", "host: file\nport: 9000\ngameConfig:\n  rounds: 3\n  maxPlayers: 4\n")
+
+	environ := []string{
+		"BASH_BATTLE_PORT=9001",
+		"BASH_BATTLE_GAMECONFIG_ROUNDS=5",
+		"BASH_BATTLE_STREAM_ONOVERFLOW=disconnect",
+		"UNRELATED=1",
+	}
+	args := []string{"-config", path, "-gameConfig.rounds", "7", "-host=flag"}
+
+	config, loadedFrom, err := Load(args, environ, &bytes.Buffer{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, path, loadedFrom)
+	assert.Equal(t, "flag", config.Host)                    // flag
+	assert.Equal(t, uint16(9001), config.Port)              // env
+	assert.Equal(t, 7, config.GameConfig.Rounds)            // flag over env
+	assert.Equal(t, 4, config.GameConfig.MaxPlayers)        // file
+	assert.Equal(t, "disconnect", config.Stream.OnOverflow) // env
+}
+
+func TestLoad_Errors(t *testing.T) {
+	path := writeFile(t, "config.json", `{}`)
+
+	_, _, err := Load([]string{"-config", path, "-port", "70000"}, nil, &bytes.Buffer{})
+	assert.ErrorContains(t, err, "-port")
+
+	_, _, err = Load([]string{"-config", path}, []string{"BASH_BATTLE_GAMECONFIG_ROUNDS=many"}, &bytes.Buffer{})
+	assert.ErrorContains(t, err, "BASH_BATTLE_GAMECONFIG_ROUNDS")
+
+	_, _, err = Load([]string{"-nope", "1"}, nil, &bytes.Buffer{})
+	assert.NotNil(t, err)
+
+	_, _, err = Load([]string{"-h"}, nil, &bytes.Buffer{})
+	assert.ErrorIs(t, err, flag.ErrHelp)
+}
+
+func TestLoad_RefusesSecretFlag(t *testing.T) {
+	path := writeFile(t, "config.json", `{}`)
+
+	for _, args := range [][]string{
+		{"-config", path, "-auth.secret", "hunter2"},
+		{"-config", path, "--auth.secret=hunter2"},
+	} {
+		_, _, err := Load(args, nil, &bytes.Buffer{})
+
+		assert.ErrorIs(t, err, ErrSecretFlag)
+		assert.ErrorContains(t, err, "BASH_BATTLE_AUTH_SECRET")
+		assert.NotContains(t, err.Error(), "hunter2")
+	}
+
+	// The environment is fine
+	config, _, err := Load([]string{"-config", path}, []string{"BASH_BATTLE_AUTH_SECRET=hunter2"}, &bytes.Buffer{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "hunter2", config.Auth.Secret)
+}
+
+func TestConfigSet_List(t *testing.T) {
+	var config Config
+
+	assert.Nil(t, config.ApplyEnv([]string{"BASH_BATTLE_USERNAMES_BLOCKLIST=foo, bar,,baz"}))
+	assert.Equal(t, []string{"foo", "bar", "baz"}, config.Usernames.Blocklist)
+}
+
+func TestConfigSet_UnknownField(t *testing.T) {
+	var config Config
+
+	assert.ErrorIs(t, config.Set("gameConfig.lives", "3"), ErrUnknownField)
+}
+
+func TestGameConfigOverride(t *testing.T) {
+	defaults := GameConfig{MaxPlayers: 4, Rounds: 3, RoundDuration: 60, Difficulty: 2, FileSize: 1}
+	rounds := int32(5)
+
+	// Set to their zero values, easy difficulty and small files still count
+	merged := defaults.Override(&proto.GameConfig{
+		Rounds:     &rounds,
+		Difficulty: proto.Difficulty_DIFFICULTY_EASY.Enum(),
+		FileSize:   proto.FileSize_FILE_SIZE_SMALL.Enum(),
+	})
+
+	assert.Equal(t, GameConfig{MaxPlayers: 4, Rounds: 5, RoundDuration: 60, Difficulty: 0, FileSize: 0}, merged)
+
+	// Nothing set, nothing overridden
+	assert.Equal(t, defaults, defaults.Override(&proto.GameConfig{}))
+	assert.Equal(t, defaults, defaults.Override(nil))
+}
+
+func TestApplyDefaults(t *testing.T) {
+	config := Config{GameConfig: GameConfig{Rounds: 3}}
+
+	config.ApplyDefaults()
+
+	assert.Equal(t, uint16(DefaultPort), config.Port)
+	assert.Equal(t, DefaultChallengesDir, config.ChallengesDir)
+	assert.Equal(t, DefaultOnOverflow, config.Stream.OnOverflow)
+	assert.Equal(t, DefaultRevokedFile, config.Auth.RevokedFile)
+	assert.Equal(t, 3, config.GameConfig.Rounds)
+	assert.Equal(t, DefaultMaxPlayers, config.GameConfig.MaxPlayers)
+	assert.Equal(t, 0, config.GameConfig.CountdownDuration)
+	assert.Nil(t, config.Validate())
+}
+
+func TestValidate_ReportsAllErrors(t *testing.T) {
+	config := Config{
+		Port:          5555,
+		ChallengesDir: "challenges",
+		Stream:        StreamConfig{QueueSize: -1},
+		Auth:          AuthConfig{TokenTTL: DefaultTokenTTL},
+		Usernames:     UsernameConfig{MinLength: 3, MaxLength: 16},
+		LogLevel:      "warn",
+		GameConfig: GameConfig{
+			MaxPlayers:         0,
+			Rounds:             1,
+			RoundDuration:      -30,
+			CountdownDuration:  10,
+			Difficulty:         3,
+			FileSize:           -1,
+			SubmissionDuration: -1,
+		},
+	}
+
+	err := config.Validate()
+
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	assert.Equal(t, "stream.queueSize: must not be negative, got -1\n"+
+		"gameConfig.maxPlayers: must be between 1 and 16, got 0\n"+
+		"gameConfig.roundDuration: must be between 1 and 3600, got -30\n"+
+		"gameConfig.difficulty: must be between 0 and 2, got 3\n"+
+		"gameConfig.fileSize: must be between 0 and 2, got -1\n"+
+		"gameConfig.submissionDuration: must be between 0 and 300, got -1", err.Error())
+
+	assert.ErrorContains(t, config.GameConfig.Validate(), "maxPlayers: must be between 1 and 16, got 0")
+}
+
+func TestValidate_ShippedConfig(t *testing.T) {
+	config, err := LoadConfig("config.json")
+
+	assert.Nil(t, err)
+	assert.Nil(t, config.Validate())
+}
+
+// newTestWatcher starts a watcher on a fake clock. Every load is sent on
+// loads, and every applied config on applied.
+func newTestWatcher(t *testing.T, path string) (*Watcher, *clock.Fake, chan error, chan Config) {
+	clk := clock.NewFake(time.Now())
+	loads := make(chan error, 1)
+	applied := make(chan Config, 1)
+
+	watcher := NewWatcher(
+		path,
+		time.Second,
+		clk,
+		func() (Config, error) {
+			config, err := LoadConfig(path)
+			loads <- err
+			return config, err
+		},
+		func(config Config) error {
+			applied <- config
+			return nil
+		},
+	)
+
+	go watcher.Run()
+	t.Cleanup(watcher.Stop)
+
+	clk.BlockUntil(1)
+
+	return watcher, clk, loads, applied
+}
+
+func waitForLoad(t *testing.T, loads chan error) {
+	select {
+	case <-loads:
+	case <-time.After(5 * time.Second):
+		t.Fatal("config was not reloaded")
+	}
+}
+
+func TestWatcher_ReloadsChangedFile(t *testing.T) {
+	path := writeFile(t, "config.yaml", "gameConfig:\n  rounds: 3\n")
+	_, clk, loads, applied := newTestWatcher(t, path)
+
+	// Invalid, the current config is kept
+	assert.Nil(t, os.WriteFile(path, []byte("gameConfig:\n  rounds: -3\n"), 0o644))
+	clk.Advance(time.Second)
+	waitForLoad(t, loads)
+
+	assert.Nil(t, os.WriteFile(path, []byte("gameConfig:\n  rounds: 5\nlogLevel: debug\n"), 0o644))
+	clk.Advance(time.Second)
+	waitForLoad(t, loads)
+
+	config := <-applied
+
+	assert.Equal(t, 5, config.GameConfig.Rounds)
+	assert.Equal(t, "debug", config.LogLevel)
+	assert.Equal(t, DefaultMaxPlayers, config.GameConfig.MaxPlayers) // Defaults applied
+	assert.Len(t, applied, 0)
+}
+
+func TestWatcher_Reload(t *testing.T) {
+	path := writeFile(t, "config.yaml", "gameConfig:\n  rounds: 3\n")
+	watcher, _, loads, applied := newTestWatcher(t, path)
+
+	// The file didn't change, it's reloaded anyway
+	watcher.Reload()
+	waitForLoad(t, loads)
+
+	config := <-applied
+
+	assert.Equal(t, 3, config.GameConfig.Rounds)
+}
+
+func TestValidate_TLS(t *testing.T) {
+	config := Config{TLS: TLSConfig{Enabled: true, RequireClientCert: true}}
+	config.ApplyDefaults()
+
+	err := config.Validate()
+
+	assert.ErrorContains(t, err, "tls.certFile: must be set when TLS is enabled")
+	assert.ErrorContains(t, err, "tls.keyFile: must be set when TLS is enabled")
+	assert.ErrorContains(t, err, "tls.clientCAFile: must be set when client certificates are required")
+}
+
+func TestValidate_Auth(t *testing.T) {
+	config := Config{Auth: AuthConfig{Secret: "too-short", TokenTTL: 10}}
+	config.ApplyDefaults()
+
+	err := config.Validate()
+
+	assert.ErrorContains(t, err, "auth.secret: must be at least 32 bytes long")
+	assert.ErrorContains(t, err, "auth.tokenTTL: must be between 60 and 604800, got 10")
+}
+
+func TestString_RedactsSecret(t *testing.T) {
+	config := Config{Auth: AuthConfig{Secret: "a-secret-that-should-not-be-logged"}}
+
+	assert.NotContains(t, config.String(), "a-secret-that-should-not-be-logged")
+	assert.Equal(t, "a-secret-that-should-not-be-logged", config.Auth.Secret)
+}
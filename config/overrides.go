@@ -0,0 +1,240 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix starts the name of every environment variable that overrides a
+// config field, e.g. BASH_BATTLE_GAMECONFIG_MAXPLAYERS.
+const EnvPrefix = "BASH_BATTLE_"
+
+var ErrUnknownField = errors.New("unknown config field")
+var ErrSecretFlag = errors.New("cannot set a secret with a flag, other processes can read the command line")
+
+// secretFields have no flag, only an environment variable: the command line
+// of the server can be read by any process on the host (ps,
+// /proc/<pid>/cmdline).
+var secretFields = map[string]bool{
+	"auth.secret": true,
+}
+
+// field is a setter for one config value, named by its path through the
+// json tags, e.g. "gameConfig.maxPlayers".
+type field struct {
+	path  string
+	value reflect.Value
+}
+
+// EnvName is the environment variable that overrides the field.
+func (f field) EnvName() string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(f.path, ".", "_"))
+}
+
+func (f field) set(raw string) error {
+	switch f.value.Kind() {
+	case reflect.String:
+		f.value.SetString(raw)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.path, err)
+		}
+		f.value.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, f.value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.path, err)
+		}
+		f.value.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, f.value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.path, err)
+		}
+		f.value.SetUint(n)
+
+	case reflect.Slice:
+		if f.value.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("%s: cannot override a %s", f.path, f.value.Type())
+		}
+
+		// A comma separated list
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		f.value.Set(reflect.ValueOf(items))
+
+	default:
+		return fmt.Errorf("%s: cannot override a %s", f.path, f.value.Kind())
+	}
+
+	return nil
+}
+
+// fields lists every overridable value of the config, in declaration order.
+func (config *Config) fields() []field {
+	return collectFields(reflect.ValueOf(config).Elem(), "")
+}
+
+func collectFields(v reflect.Value, prefix string) []field {
+	var fields []field
+
+	for i := 0; i < v.NumField(); i++ {
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+
+		if name == "" || name == "-" {
+			continue
+		}
+
+		path := prefix + name
+
+		if v.Field(i).Kind() == reflect.Struct {
+			fields = append(fields, collectFields(v.Field(i), path+".")...)
+		} else {
+			fields = append(fields, field{path: path, value: v.Field(i)})
+		}
+	}
+
+	return fields
+}
+
+// Set overrides the config field at path (e.g. "gameConfig.rounds") with a
+// raw value, parsed according to the field's type.
+func (config *Config) Set(path string, raw string) error {
+	for _, f := range config.fields() {
+		if f.path == path {
+			return f.set(raw)
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownField, path)
+}
+
+// ApplyEnv overrides config fields with the matching BASH_BATTLE_ variables
+// of environ, given as "KEY=value" pairs like os.Environ returns.
+func (config *Config) ApplyEnv(environ []string) error {
+	env := make(map[string]string)
+
+	for _, kv := range environ {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			env[key] = value
+		}
+	}
+
+	var errs []error
+
+	for _, f := range config.fields() {
+		if raw, ok := env[f.EnvName()]; ok {
+			if err := f.set(raw); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", f.EnvName(), err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// override records a flag given on the command line, to be applied once the
+// config file is loaded.
+type override struct {
+	path string
+	set  *[]override
+	raw  string
+}
+
+func (o *override) String() string { return "" }
+
+func (o *override) Set(raw string) error {
+	*o.set = append(*o.set, override{path: o.path, raw: raw})
+	return nil
+}
+
+// Load builds the effective config: the config file (-config, or
+// DefaultPath), then environment variables, then flags, each taking
+// precedence over the last. Every config field has a flag named by its
+// path, e.g. -port or -gameConfig.rounds, except for the secret ones.
+//
+// Returns flag.ErrHelp if -h or -help was given.
+func Load(args []string, environ []string, output io.Writer) (Config, string, error) {
+	flags := flag.NewFlagSet("bash-battle-server", flag.ContinueOnError)
+	flags.SetOutput(output)
+
+	path := flags.String("config", DefaultPath, "path to the config file (.json, .yaml, .yml or .toml)")
+
+	var overrides []override
+	var template Config
+
+	for _, f := range template.fields() {
+		if secretFields[f.path] {
+			continue
+		}
+
+		usage := fmt.Sprintf("override %s (env %s)", f.path, f.EnvName())
+		flags.Var(&override{path: f.path, set: &overrides}, f.path, usage)
+	}
+
+	if err := refuseSecretFlags(args); err != nil {
+		return Config{}, "", err
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return Config{}, "", err
+	}
+
+	if flags.NArg() > 0 {
+		return Config{}, "", fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+
+	config, err := LoadConfig(*path)
+
+	if err != nil {
+		return config, *path, err
+	}
+
+	if err := config.ApplyEnv(environ); err != nil {
+		return config, *path, err
+	}
+
+	for _, o := range overrides {
+		if err := config.Set(o.path, o.raw); err != nil {
+			return config, *path, fmt.Errorf("-%s: %w", o.path, err)
+		}
+	}
+
+	return config, *path, nil
+}
+
+// refuseSecretFlags fails if args set a secret field. Checked before the
+// flags are parsed, so that the value doesn't end up in the error.
+func refuseSecretFlags(args []string) error {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		name := strings.TrimLeft(arg, "-")
+
+		if name == arg {
+			continue // Not a flag
+		}
+
+		name, _, _ = strings.Cut(name, "=")
+
+		if secretFields[name] {
+			return fmt.Errorf("%w: -%s, use %s instead", ErrSecretFlag, name, field{path: name}.EnvName())
+		}
+	}
+
+	return nil
+}
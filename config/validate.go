@@ -0,0 +1,199 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/maria-mz/bash-battle-server/log"
+)
+
+var ErrInvalidConfig = errors.New("invalid config")
+
+// Limits on game config values. Difficulty and FileSize are enums, see
+// game.Difficulty and game.MaxFileSize.
+const (
+	MaxMaxPlayers         = 16
+	MaxRounds             = 50
+	MaxRoundDuration      = 3600
+	MaxCountdownDuration  = 300
+	MaxAckTimeout         = 60
+	MaxSubmissionDuration = 300
+	MaxDifficulty         = 2
+	MaxFileSize           = 2
+)
+
+// MaxUsernameLength is the most usernames.maxLength can be set to.
+const MaxUsernameLength = 32
+
+// Limits on auth config values.
+const (
+	MinSecretLength = 32
+	MinTokenTTL     = 60
+	MaxTokenTTL     = 7 * 24 * 3600
+)
+
+// Defaults for fields left out of the config file.
+const (
+	DefaultPort          = 5555
+	DefaultChallengesDir = "challenges"
+	DefaultQueueSize     = 64
+	DefaultOnOverflow    = "dropOldest"
+	DefaultLogLevel      = "info"
+	DefaultTokenTTL      = 3600
+	DefaultRevokedFile   = "revoked.json"
+
+	DefaultMinUsernameLength = 3
+	DefaultMaxUsernameLength = 16
+
+	DefaultMaxPlayers    = 4
+	DefaultRounds        = 10
+	DefaultRoundDuration = 300
+)
+
+// FieldError is a problem with one config field, named by its path, e.g.
+// "gameConfig.rounds".
+type FieldError struct {
+	Path    string
+	Problem string
+}
+
+func (err *FieldError) Error() string {
+	return err.Path + ": " + err.Problem
+}
+
+func (err *FieldError) Is(target error) bool {
+	return target == ErrInvalidConfig
+}
+
+// ApplyDefaults fills in the fields that aren't set. An empty host is left
+// as is, it listens on all interfaces.
+func (config *Config) ApplyDefaults() {
+	if config.Port == 0 {
+		config.Port = DefaultPort
+	}
+	if config.ChallengesDir == "" {
+		config.ChallengesDir = DefaultChallengesDir
+	}
+	if config.Stream.QueueSize == 0 {
+		config.Stream.QueueSize = DefaultQueueSize
+	}
+	if config.Stream.OnOverflow == "" {
+		config.Stream.OnOverflow = DefaultOnOverflow
+	}
+	if config.LogLevel == "" {
+		config.LogLevel = DefaultLogLevel
+	}
+	if config.Auth.TokenTTL == 0 {
+		config.Auth.TokenTTL = DefaultTokenTTL
+	}
+	if config.Auth.RevokedFile == "" {
+		config.Auth.RevokedFile = DefaultRevokedFile
+	}
+	if config.Usernames.MinLength == 0 {
+		config.Usernames.MinLength = DefaultMinUsernameLength
+	}
+	if config.Usernames.MaxLength == 0 {
+		config.Usernames.MaxLength = DefaultMaxUsernameLength
+	}
+
+	config.GameConfig.ApplyDefaults()
+}
+
+// ApplyDefaults fills in the fields that aren't set. A CountdownDuration of
+// 0 is kept, rounds then start right away.
+func (config *GameConfig) ApplyDefaults() {
+	if config.MaxPlayers == 0 {
+		config.MaxPlayers = DefaultMaxPlayers
+	}
+	if config.Rounds == 0 {
+		config.Rounds = DefaultRounds
+	}
+	if config.RoundDuration == 0 {
+		config.RoundDuration = DefaultRoundDuration
+	}
+	if config.AckTimeout == 0 {
+		config.AckTimeout = int(DefaultAckTimeout.Seconds())
+	}
+	if config.SubmissionDuration == 0 {
+		config.SubmissionDuration = int(DefaultSubmissionDuration.Seconds())
+	}
+}
+
+// Validate reports every invalid field of the config at once, joined into
+// one error. Each one is a *FieldError, matching ErrInvalidConfig.
+func (config *Config) Validate() error {
+	var errs []error
+
+	if config.Port == 0 {
+		errs = append(errs, &FieldError{"port", "must be set"})
+	}
+	if config.ChallengesDir == "" {
+		errs = append(errs, &FieldError{"challengesDir", "must be set"})
+	}
+	if config.Stream.QueueSize < 0 {
+		errs = append(errs, &FieldError{"stream.queueSize", fmt.Sprintf("must not be negative, got %d", config.Stream.QueueSize)})
+	}
+
+	if config.TLS.Enabled {
+		if config.TLS.CertFile == "" {
+			errs = append(errs, &FieldError{"tls.certFile", "must be set when TLS is enabled"})
+		}
+		if config.TLS.KeyFile == "" {
+			errs = append(errs, &FieldError{"tls.keyFile", "must be set when TLS is enabled"})
+		}
+		if config.TLS.RequireClientCert && config.TLS.ClientCAFile == "" {
+			errs = append(errs, &FieldError{"tls.clientCAFile", "must be set when client certificates are required"})
+		}
+	}
+
+	if config.Auth.Secret != "" && len(config.Auth.Secret) < MinSecretLength {
+		errs = append(errs, &FieldError{"auth.secret", fmt.Sprintf("must be at least %d bytes long", MinSecretLength)})
+	}
+	if config.Auth.TokenTTL < MinTokenTTL || config.Auth.TokenTTL > MaxTokenTTL {
+		errs = append(errs, &FieldError{"auth.tokenTTL", fmt.Sprintf("must be between %d and %d, got %d", MinTokenTTL, MaxTokenTTL, config.Auth.TokenTTL)})
+	}
+
+	if config.Usernames.MinLength < 1 || config.Usernames.MinLength > MaxUsernameLength {
+		errs = append(errs, &FieldError{"usernames.minLength", fmt.Sprintf("must be between 1 and %d, got %d", MaxUsernameLength, config.Usernames.MinLength)})
+	} else if config.Usernames.MaxLength < config.Usernames.MinLength || config.Usernames.MaxLength > MaxUsernameLength {
+		errs = append(errs, &FieldError{"usernames.maxLength", fmt.Sprintf("must be between %d and %d, got %d", config.Usernames.MinLength, MaxUsernameLength, config.Usernames.MaxLength)})
+	}
+
+	if _, err := log.ParseLevel(config.LogLevel); err != nil {
+		errs = append(errs, &FieldError{"logLevel", err.Error()})
+	}
+
+	errs = append(errs, config.GameConfig.validate("gameConfig.")...)
+
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid field of the game config at once. Field
+// paths are relative to the game config, e.g. "rounds".
+func (config *GameConfig) Validate() error {
+	return errors.Join(config.validate("")...)
+}
+
+func (config *GameConfig) validate(prefix string) []error {
+	var errs []error
+
+	inRange := func(name string, value int, min int, max int) {
+		if value < min || value > max {
+			errs = append(errs, &FieldError{
+				Path:    prefix + name,
+				Problem: fmt.Sprintf("must be between %d and %d, got %d", min, max, value),
+			})
+		}
+	}
+
+	inRange("maxPlayers", config.MaxPlayers, 1, MaxMaxPlayers)
+	inRange("rounds", config.Rounds, 1, MaxRounds)
+	inRange("roundDuration", config.RoundDuration, 1, MaxRoundDuration)
+	inRange("countdownDuration", config.CountdownDuration, 0, MaxCountdownDuration)
+	inRange("difficulty", config.Difficulty, 0, MaxDifficulty)
+	inRange("fileSize", config.FileSize, 0, MaxFileSize)
+	inRange("ackTimeout", config.AckTimeout, 0, MaxAckTimeout)
+	inRange("submissionDuration", config.SubmissionDuration, 0, MaxSubmissionDuration)
+
+	return errs
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"crypto/sha256"
+	"os"
+	"time"
+
+	"github.com/maria-mz/bash-battle-server/clock"
+	"github.com/maria-mz/bash-battle-server/log"
+)
+
+// DefaultWatchInterval is how often a Watcher checks the config file.
+const DefaultWatchInterval = 2 * time.Second
+
+// Watcher reloads the config when its file changes, or when Reload is
+// called (on SIGHUP). A new config is loaded with load, which should apply
+// the same overrides as at startup, and handed to apply. If either fails,
+// the current config is kept.
+type Watcher struct {
+	path     string
+	interval time.Duration
+	clock    clock.Clock
+	load     func() (Config, error)
+	apply    func(Config) error
+
+	checksum [sha256.Size]byte // Of the file contents last seen
+	reload   chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func NewWatcher(
+	path string,
+	interval time.Duration,
+	clk clock.Clock,
+	load func() (Config, error),
+	apply func(Config) error,
+) *Watcher {
+	w := &Watcher{
+		path:     path,
+		interval: interval,
+		clock:    clk,
+		load:     load,
+		apply:    apply,
+		reload:   make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	w.checksum, _ = w.readChecksum()
+
+	return w
+}
+
+// Run checks the file every interval until Stop is called.
+func (w *Watcher) Run() {
+	defer close(w.done)
+
+	ticker := w.clock.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+
+		case <-w.reload:
+			w.checksum, _ = w.readChecksum()
+			w.reloadConfig("reload requested")
+
+		case <-ticker.C():
+			checksum, err := w.readChecksum()
+
+			if err != nil || checksum == w.checksum {
+				continue // Missing files are likely mid-write, wait for the next tick
+			}
+
+			w.checksum = checksum
+			w.reloadConfig("config file changed")
+		}
+	}
+}
+
+// Reload asks the watcher to reload the config, whether the file changed
+// or not.
+func (w *Watcher) Reload() {
+	select {
+	case w.reload <- struct{}{}:
+	default: // A reload is already pending
+	}
+}
+
+// Stop stops a running watcher, and waits for it to finish a reload in
+// progress.
+func (w *Watcher) Stop() {
+	close(w.stop)
+	<-w.done
+}
+
+func (w *Watcher) readChecksum() ([sha256.Size]byte, error) {
+	data, err := os.ReadFile(w.path)
+
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+
+	return sha256.Sum256(data), nil
+}
+
+func (w *Watcher) reloadConfig(reason string) {
+	log.Logger.Info("Reloading server config", "path", w.path, "reason", reason)
+
+	config, err := w.load()
+
+	if err == nil {
+		config.ApplyDefaults()
+		err = config.Validate()
+	}
+
+	if err == nil {
+		err = w.apply(config)
+	}
+
+	if err != nil {
+		log.Logger.Error("Keeping the current server config", "err", err)
+		return
+	}
+
+	log.Logger.Info("Reloaded server config", "config", config)
+}
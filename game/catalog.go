@@ -0,0 +1,268 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/maria-mz/bash-battle-server/config"
+)
+
+const (
+	challengeSpecFile = "challenge.json"
+	defaultInputFile  = "input.txt"
+	defaultOutputFile = "output.txt"
+)
+
+var ErrEmptyQuestion = errors.New("question is empty")
+var ErrNotEnoughChallenges = errors.New("not enough challenges in catalog")
+var ErrWrongSolution = errors.New("solution does not produce the expected output")
+
+// challengeSpec is the on-disk format of a challenge (challenge.json).
+type challengeSpec struct {
+	Question   string   `json:"question"`
+	Difficulty string   `json:"difficulty"`
+	Tags       []string `json:"tags"`
+	Solution   string   `json:"solution"`
+	InputFile  string   `json:"inputFile"`
+	OutputFile string   `json:"outputFile"`
+}
+
+// Catalog holds every challenge the server can hand out.
+//
+// On disk, each challenge lives in its own directory (the directory name is
+// the challenge ID) with a challenge.json describing it, plus the input file
+// and expected output file (input.txt and output.txt unless overridden).
+type Catalog struct {
+	challenges []Challenge
+}
+
+// NewCatalog creates a catalog from challenges that are already loaded.
+func NewCatalog(challenges ...Challenge) *Catalog {
+	return &Catalog{challenges: challenges}
+}
+
+// LoadCatalog loads and validates every challenge under dir. All invalid
+// challenges are reported together in the returned error.
+func LoadCatalog(dir string) (*Catalog, error) {
+	entries, err := os.ReadDir(dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := &Catalog{}
+	var errs []error
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		challenge, err := loadChallenge(filepath.Join(dir, entry.Name()))
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("challenge %q: %w", entry.Name(), err))
+			continue
+		}
+
+		catalog.challenges = append(catalog.challenges, challenge)
+	}
+
+	if len(errs) != 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return catalog, nil
+}
+
+func loadChallenge(dir string) (Challenge, error) {
+	var spec challengeSpec
+
+	data, err := os.ReadFile(filepath.Join(dir, challengeSpecFile))
+
+	if err != nil {
+		return Challenge{}, err
+	}
+
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return Challenge{}, err
+	}
+
+	if spec.InputFile == "" {
+		spec.InputFile = defaultInputFile
+	}
+	if spec.OutputFile == "" {
+		spec.OutputFile = defaultOutputFile
+	}
+
+	challenge := Challenge{
+		ID:         filepath.Base(dir),
+		Question:   spec.Question,
+		Tags:       spec.Tags,
+		Solution:   spec.Solution,
+		InputFile:  FilePath(filepath.Join(dir, spec.InputFile)),
+		OutputFile: FilePath(filepath.Join(dir, spec.OutputFile)),
+	}
+
+	var errs []error
+
+	if challenge.Question == "" {
+		errs = append(errs, ErrEmptyQuestion)
+	}
+
+	challenge.Difficulty, err = ParseDifficulty(spec.Difficulty)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	challenge.InputSize, err = regularFileSize(challenge.InputFile)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	challenge.OutputSize, err = regularFileSize(challenge.OutputFile)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return challenge, errors.Join(errs...)
+}
+
+func regularFileSize(path FilePath) (int64, error) {
+	info, err := os.Stat(string(path))
+
+	if err != nil {
+		return 0, err
+	}
+
+	if !info.Mode().IsRegular() {
+		return 0, fmt.Errorf("%s is not a regular file", path)
+	}
+
+	return info.Size(), nil
+}
+
+// CheckSolutions runs the reference solution of every challenge that has
+// one, and reports all challenges whose solution doesn't produce the
+// expected output.
+func (catalog *Catalog) CheckSolutions(verifier *Verifier) error {
+	var errs []error
+
+	for _, challenge := range catalog.challenges {
+		if challenge.Solution == "" {
+			continue
+		}
+
+		ok, err := verifier.Verify(challenge, challenge.Solution)
+
+		if err == nil && !ok {
+			err = ErrWrongSolution
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("challenge %q: %w", challenge.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Size returns the number of challenges in the catalog.
+func (catalog *Catalog) Size() int {
+	return len(catalog.challenges)
+}
+
+// Challenges returns the challenges matching the difficulty, sorted by ID.
+func (catalog *Catalog) Challenges(difficulty Difficulty) []Challenge {
+	var challenges []Challenge
+
+	for _, challenge := range catalog.challenges {
+		if challenge.Difficulty == difficulty {
+			challenges = append(challenges, challenge)
+		}
+	}
+
+	sort.Slice(challenges, func(i, j int) bool {
+		return challenges[i].ID < challenges[j].ID
+	})
+
+	return challenges
+}
+
+// candidates returns the challenges a game with the given config may use:
+// those matching its difficulty whose files fit its file size limit.
+func (catalog *Catalog) candidates(config config.GameConfig) []Challenge {
+	var candidates []Challenge
+
+	maxFileSize := MaxFileSize(config.FileSize)
+
+	for _, challenge := range catalog.Challenges(Difficulty(config.Difficulty)) {
+		if challenge.FitsFileSize(maxFileSize) {
+			candidates = append(candidates, challenge)
+		}
+	}
+
+	return candidates
+}
+
+// CanServe checks that the catalog has enough challenges to play a game
+// with the given config.
+func (catalog *Catalog) CanServe(config config.GameConfig) error {
+	available := len(catalog.candidates(config))
+
+	if available < config.Rounds {
+		return fmt.Errorf(
+			"%w: %d rounds of %s difficulty (files up to %d bytes) requested, %d available",
+			ErrNotEnoughChallenges,
+			config.Rounds,
+			Difficulty(config.Difficulty),
+			MaxFileSize(config.FileSize),
+			available,
+		)
+	}
+
+	return nil
+}
+
+// Select randomly picks a challenge for each round of a game, without
+// repeats. Challenges are keyed by round, 0-based.
+func (catalog *Catalog) Select(config config.GameConfig) (map[int]Challenge, error) {
+	if err := catalog.CanServe(config); err != nil {
+		return nil, err
+	}
+
+	candidates := catalog.candidates(config)
+	order := rand.Perm(len(candidates))
+
+	challenges := make(map[int]Challenge)
+
+	for i := 0; i < config.Rounds; i++ {
+		challenges[i] = candidates[order[i]]
+	}
+
+	return challenges, nil
+}
+
+// Alternatives returns the challenges a game with the given config may use
+// in place of one of its own, in random order. Challenges whose IDs are in
+// exclude are left out.
+func (catalog *Catalog) Alternatives(config config.GameConfig, exclude map[string]bool) []Challenge {
+	var alternatives []Challenge
+
+	for _, challenge := range catalog.candidates(config) {
+		if !exclude[challenge.ID] {
+			alternatives = append(alternatives, challenge)
+		}
+	}
+
+	rand.Shuffle(len(alternatives), func(i, j int) {
+		alternatives[i], alternatives[j] = alternatives[j], alternatives[i]
+	})
+
+	return alternatives
+}
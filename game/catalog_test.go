@@ -0,0 +1,148 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maria-mz/bash-battle-server/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeChallenge(t *testing.T, dir string, id string, spec string) {
+	challengeDir := filepath.Join(dir, id)
+
+	assert.Nil(t, os.Mkdir(challengeDir, 0o755))
+	assert.Nil(t, os.WriteFile(filepath.Join(challengeDir, challengeSpecFile), []byte(spec), 0o644))
+
+	writeChallengeFiles(t, challengeDir, "b\na\n", "a\nb\n")
+}
+
+func TestLoadCatalog_Ok(t *testing.T) {
+	dir := t.TempDir()
+
+	writeChallenge(t, dir, "sort", `{"question": "Sort it", "difficulty": "easy", "tags": ["sort"]}`)
+	writeChallenge(t, dir, "sort-hard", `{"question": "Sort it harder", "difficulty": "HARD"}`)
+
+	catalog, err := LoadCatalog(dir)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, catalog.Size())
+
+	easy := catalog.Challenges(Easy)
+
+	assert.Len(t, easy, 1)
+	assert.Equal(t, "sort", easy[0].ID)
+	assert.Equal(t, "Sort it", easy[0].Question)
+	assert.Equal(t, []string{"sort"}, easy[0].Tags)
+	assert.Equal(t, FilePath(filepath.Join(dir, "sort", "input.txt")), easy[0].InputFile)
+	assert.Equal(t, FilePath(filepath.Join(dir, "sort", "output.txt")), easy[0].OutputFile)
+
+	assert.Len(t, catalog.Challenges(Hard), 1)
+	assert.Len(t, catalog.Challenges(Medium), 0)
+}
+
+func TestLoadCatalog_ReportsAllErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	writeChallenge(t, dir, "no-question", `{"difficulty": "easy"}`)
+	writeChallenge(t, dir, "bad-difficulty", `{"question": "?", "difficulty": "impossible"}`)
+	writeChallenge(t, dir, "missing-file", `{"question": "?", "difficulty": "easy", "inputFile": "nope.txt"}`)
+
+	catalog, err := LoadCatalog(dir)
+
+	assert.Nil(t, catalog)
+	assert.ErrorIs(t, err, ErrEmptyQuestion)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.ErrorContains(t, err, `challenge "bad-difficulty": unknown difficulty "impossible"`)
+}
+
+func TestLoadCatalog_ShippedChallenges(t *testing.T) {
+	catalog, err := LoadCatalog("../challenges")
+
+	assert.Nil(t, err)
+	assert.NotZero(t, catalog.Size())
+	assert.Nil(t, catalog.CheckSolutions(newTestVerifier(t)))
+}
+
+func TestCatalogSelect(t *testing.T) {
+	catalog := NewCatalog(
+		Challenge{ID: "easy-1", Difficulty: Easy},
+		Challenge{ID: "easy-2", Difficulty: Easy},
+		Challenge{ID: "easy-3", Difficulty: Easy},
+		Challenge{ID: "medium-1", Difficulty: Medium},
+		Challenge{ID: "medium-2", Difficulty: Medium},
+	)
+
+	conf := config.GameConfig{Rounds: 3, Difficulty: int(Easy)}
+
+	for i := 0; i < 20; i++ {
+		challenges, err := catalog.Select(conf)
+
+		assert.Nil(t, err)
+		assert.Len(t, challenges, 3)
+
+		seen := make(map[string]bool)
+
+		for round := 0; round < conf.Rounds; round++ {
+			challenge, ok := challenges[round]
+
+			assert.True(t, ok)
+			assert.Equal(t, Easy, challenge.Difficulty)
+			assert.False(t, seen[challenge.ID], "challenge repeated")
+
+			seen[challenge.ID] = true
+		}
+	}
+}
+
+func TestCatalogSelect_ErrNotEnoughChallenges(t *testing.T) {
+	catalog := NewCatalog(
+		Challenge{ID: "easy-1", Difficulty: Easy},
+		Challenge{ID: "medium-1", Difficulty: Medium},
+	)
+
+	conf := config.GameConfig{Rounds: 2, Difficulty: int(Medium)}
+
+	challenges, err := catalog.Select(conf)
+
+	assert.Nil(t, challenges)
+	assert.ErrorIs(t, err, ErrNotEnoughChallenges)
+	assert.ErrorIs(t, catalog.CanServe(conf), ErrNotEnoughChallenges)
+}
+
+func TestCatalogSelect_RespectsFileSize(t *testing.T) {
+	catalog := NewCatalog(
+		Challenge{ID: "small", InputSize: 100, OutputSize: 100},
+		Challenge{ID: "large", InputSize: 1 << 20, OutputSize: 100},
+	)
+
+	conf := config.GameConfig{Rounds: 1, FileSize: 0}
+
+	for i := 0; i < 10; i++ {
+		challenges, err := catalog.Select(conf)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "small", challenges[0].ID)
+	}
+
+	conf.Rounds = 2
+
+	_, err := catalog.Select(conf)
+	assert.ErrorIs(t, err, ErrNotEnoughChallenges)
+}
+
+func TestCatalogAlternatives(t *testing.T) {
+	catalog := NewCatalog(
+		Challenge{ID: "easy-1", Difficulty: Easy},
+		Challenge{ID: "easy-2", Difficulty: Easy},
+		Challenge{ID: "easy-3", Difficulty: Easy},
+		Challenge{ID: "medium-1", Difficulty: Medium},
+	)
+
+	conf := config.GameConfig{Rounds: 1, Difficulty: int(Easy)}
+
+	alternatives := catalog.Alternatives(conf, map[string]bool{"easy-2": true})
+
+	assert.ElementsMatch(t, []Challenge{catalog.challenges[0], catalog.challenges[2]}, alternatives)
+}
@@ -1,36 +1,122 @@
 package game
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
-
-	"github.com/maria-mz/bash-battle-server/config"
+	"os"
+	"strings"
 )
 
+var ErrFileTooLarge = errors.New("file exceeds the size limit")
+
 type FilePath string
 
+type Difficulty int
+
+const (
+	Easy Difficulty = iota
+	Medium
+	Hard
+)
+
+var difficultyNames = map[Difficulty]string{
+	Easy:   "easy",
+	Medium: "medium",
+	Hard:   "hard",
+}
+
+func (difficulty Difficulty) String() string {
+	name, ok := difficultyNames[difficulty]
+	if !ok {
+		return fmt.Sprintf("Difficulty(%d)", int(difficulty))
+	}
+	return name
+}
+
+// ParseDifficulty returns the Difficulty matching the name (case-insensitive).
+func ParseDifficulty(name string) (Difficulty, error) {
+	for difficulty, difficultyName := range difficultyNames {
+		if strings.EqualFold(name, difficultyName) {
+			return difficulty, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown difficulty %q", name)
+}
+
 type Challenge struct {
+	ID         string
 	Question   string
+	Difficulty Difficulty
+	Tags       []string
+	Solution   string // Reference answer, optional
 	InputFile  FilePath
 	OutputFile FilePath
+	InputSize  int64 // Bytes, as seen when the catalog was loaded
+	OutputSize int64 // Bytes, as seen when the catalog was loaded
+}
+
+// ChallengeFiles holds the contents of a challenge's files, as sent to
+// players when a round loads.
+type ChallengeFiles struct {
+	Input          []byte
+	Output         []byte
+	InputChecksum  string // SHA-256, hex encoded
+	OutputChecksum string // SHA-256, hex encoded
 }
 
 func (challenge *Challenge) InfoString() string {
 	return fmt.Sprintf("%+v", challenge)
 }
 
-// TODO: temporary, implement real functionality, randomly assign rounds, use db
-func GenerateChallenges(config config.GameConfig) map[int]Challenge {
-	challenges := make(map[int]Challenge)
+// FitsFileSize checks if both challenge files are at most maxSize bytes.
+func (challenge *Challenge) FitsFileSize(maxSize int64) bool {
+	return challenge.InputSize <= maxSize && challenge.OutputSize <= maxSize
+}
+
+// ReadFiles reads the input and expected output files of the challenge.
+// Fails with ErrFileTooLarge if either file is larger than maxSize bytes.
+func (challenge *Challenge) ReadFiles(maxSize int64) (ChallengeFiles, error) {
+	var files ChallengeFiles
+
+	input, err := readFile(challenge.InputFile, maxSize)
+
+	if err != nil {
+		return files, err
+	}
+
+	output, err := readFile(challenge.OutputFile, maxSize)
+
+	if err != nil {
+		return files, err
+	}
+
+	files.Input = input
+	files.Output = output
+	files.InputChecksum = checksum(input)
+	files.OutputChecksum = checksum(output)
+
+	return files, nil
+}
 
-	tempChallenge := Challenge{
-		Question:   "???",
-		InputFile:  "input.txt",
-		OutputFile: "output.txt",
+func readFile(path FilePath, maxSize int64) ([]byte, error) {
+	data, err := os.ReadFile(string(path))
+
+	if err != nil {
+		return nil, err
 	}
 
-	for i := 0; i < config.Rounds; i++ {
-		challenges[i] = tempChallenge
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf(
+			"%w: %s is %d bytes, limit is %d", ErrFileTooLarge, path, len(data), maxSize,
+		)
 	}
 
-	return challenges
+	return data, nil
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
 }
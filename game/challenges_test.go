@@ -0,0 +1,67 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeChallengeFiles writes a challenge's input.txt and output.txt to
+// dir. The game package can't use gametest, which imports it.
+func writeChallengeFiles(t *testing.T, dir string, input string, output string) {
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "input.txt"), []byte(input), 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "output.txt"), []byte(output), 0o644))
+}
+
+func newFileChallenge(t *testing.T, input string, output string) Challenge {
+	dir := t.TempDir()
+
+	writeChallengeFiles(t, dir, input, output)
+
+	return Challenge{
+		ID:         "test",
+		InputFile:  FilePath(filepath.Join(dir, "input.txt")),
+		OutputFile: FilePath(filepath.Join(dir, "output.txt")),
+	}
+}
+
+func TestParseDifficulty(t *testing.T) {
+	difficulty, err := ParseDifficulty("Medium")
+
+	assert.Nil(t, err)
+	assert.Equal(t, Medium, difficulty)
+
+	_, err = ParseDifficulty("extreme")
+	assert.NotNil(t, err)
+}
+
+func TestReadFiles_Ok(t *testing.T) {
+	challenge := newFileChallenge(t, "hello\n", "HELLO\n")
+
+	files, err := challenge.ReadFiles(64)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("hello\n"), files.Input)
+	assert.Equal(t, []byte("HELLO\n"), files.Output)
+	// sha256sum of the file contents
+	assert.Equal(t, "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03", files.InputChecksum)
+	assert.Equal(t, "3b09aeb6f5f5336beb205d7f720371bc927cd46c21922e334d47ba264acb5ba4", files.OutputChecksum)
+}
+
+func TestReadFiles_ErrFileTooLarge(t *testing.T) {
+	challenge := newFileChallenge(t, "0123456789", "ok")
+
+	_, err := challenge.ReadFiles(5)
+
+	assert.ErrorIs(t, err, ErrFileTooLarge)
+}
+
+func TestReadFiles_MissingFile(t *testing.T) {
+	challenge := Challenge{InputFile: "does-not-exist.txt"}
+
+	_, err := challenge.ReadFiles(64)
+
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -6,16 +6,34 @@ import (
 	"github.com/maria-mz/bash-battle-server/config"
 )
 
+// maxFileSizes maps GameConfig.FileSize to the largest challenge file, in
+// bytes, a game may send to players.
+var maxFileSizes = map[int]int64{
+	0: 4 << 10,  // small
+	1: 16 << 10, // medium
+	2: 64 << 10, // large
+}
+
+// MaxFileSize returns the file size limit, in bytes, for a GameConfig.FileSize.
+// Unknown values fall back to the smallest limit.
+func MaxFileSize(fileSize int) int64 {
+	maxSize, ok := maxFileSizes[fileSize]
+	if !ok {
+		return maxFileSizes[0]
+	}
+	return maxSize
+}
+
 type GameData struct {
 	Config     config.GameConfig
 	Challenges map[int]Challenge
 	Players    map[string]*Player
 }
 
-func NewGameData(config config.GameConfig) *GameData {
+func NewGameData(config config.GameConfig, challenges map[int]Challenge) *GameData {
 	return &GameData{
 		Config:     config,
-		Challenges: GenerateChallenges(config),
+		Challenges: challenges,
 		Players:    make(map[string]*Player),
 	}
 }
@@ -27,11 +45,14 @@ func (data *GameData) HasPlayer(name string) bool {
 
 func (data *GameData) GetPlayer(name string) (Player, bool) {
 	player, ok := data.Players[name]
+	if !ok {
+		return Player{}, false
+	}
 	return *player, ok
 }
 
 func (data *GameData) GetPlayers() []*Player {
-	players := make([]*Player, len(data.Players))
+	players := make([]*Player, 0, len(data.Players))
 
 	for _, player := range data.Players {
 		players = append(players, player)
@@ -52,11 +73,23 @@ func (data *GameData) RemovePlayer(name string) {
 	delete(data.Players, name)
 }
 
+// SetPlayerAbsent marks a player as absent or back. No-op if there is no
+// such player.
+func (data *GameData) SetPlayerAbsent(name string, absent bool) {
+	if player, ok := data.Players[name]; ok {
+		player.Absent = absent
+	}
+}
+
 func (data *GameData) GetChallenge(round int) (Challenge, bool) {
 	challenge, ok := data.Challenges[round]
 	return challenge, ok
 }
 
+func (data *GameData) GetMaxFileSize() int64 {
+	return MaxFileSize(data.Config.FileSize)
+}
+
 func (data *GameData) GetRoundDuration() time.Duration {
 	return time.Duration(data.Config.RoundDuration) * time.Second
 }
@@ -0,0 +1,51 @@
+// Package gametest has the challenge fixtures shared by the tests of the
+// packages that run games.
+package gametest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maria-mz/bash-battle-server/game"
+	"github.com/maria-mz/bash-battle-server/game/sandbox"
+	"github.com/stretchr/testify/assert"
+)
+
+// SortInput and SortOutput are the files of a challenge solved by sorting
+// input.txt.
+const (
+	SortInput  = "b\na\n"
+	SortOutput = "a\nb\n"
+)
+
+// FileChallenge returns a challenge whose input and output files are
+// written to a temporary directory.
+func FileChallenge(t *testing.T, id string, input string, output string) game.Challenge {
+	dir := t.TempDir()
+
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "input.txt"), []byte(input), 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "output.txt"), []byte(output), 0o644))
+
+	return game.Challenge{
+		ID:         id,
+		InputFile:  game.FilePath(filepath.Join(dir, "input.txt")),
+		OutputFile: game.FilePath(filepath.Join(dir, "output.txt")),
+	}
+}
+
+// SortCatalog returns a catalog whose challenges are solved by sorting
+// input.txt.
+func SortCatalog(t *testing.T) *game.Catalog {
+	challenge := FileChallenge(t, "sort", SortInput, SortOutput)
+
+	return game.NewCatalog(challenge, challenge)
+}
+
+// Verifier returns a verifier with the sandbox config the server uses.
+func Verifier(t *testing.T) *game.Verifier {
+	verifier, err := game.NewVerifier(sandbox.DefaultConfig())
+	assert.Nil(t, err)
+
+	return verifier
+}
@@ -0,0 +1,76 @@
+package game
+
+import (
+	"sort"
+	"time"
+)
+
+// Standing is a player's place on the leaderboard.
+type Standing struct {
+	Rank      int // 1-based, tied players share a rank
+	Player    string
+	Points    int
+	RoundsWon int
+	SolveTime time.Duration // Total time taken to win rounds
+}
+
+// ahead checks if standing ranks ahead of other: more points, then more
+// rounds won, then less total time spent winning them.
+func (standing Standing) ahead(other Standing) bool {
+	if standing.Points != other.Points {
+		return standing.Points > other.Points
+	}
+	if standing.RoundsWon != other.RoundsWon {
+		return standing.RoundsWon > other.RoundsWon
+	}
+	return standing.SolveTime < other.SolveTime
+}
+
+// BuildLeaderboard ranks the players. Players who can't be separated by
+// any tie-breaker share a rank, and are listed by name.
+func BuildLeaderboard(players []*Player) []Standing {
+	standings := make([]Standing, 0, len(players))
+
+	for _, player := range players {
+		standings = append(standings, Standing{
+			Player:    player.Name,
+			Points:    player.TotalPoints(),
+			RoundsWon: player.RoundsWon(),
+			SolveTime: player.TotalSolveTime(),
+		})
+	}
+
+	sort.Slice(standings, func(i, j int) bool {
+		if standings[i].ahead(standings[j]) {
+			return true
+		}
+		if standings[j].ahead(standings[i]) {
+			return false
+		}
+		return standings[i].Player < standings[j].Player
+	})
+
+	for i := range standings {
+		if i > 0 && !standings[i-1].ahead(standings[i]) {
+			standings[i].Rank = standings[i-1].Rank
+		} else {
+			standings[i].Rank = i + 1
+		}
+	}
+
+	return standings
+}
+
+// Winners returns the names of the players ranked first. More than one
+// if they are tied.
+func Winners(standings []Standing) []string {
+	var winners []string
+
+	for _, standing := range standings {
+		if standing.Rank == 1 {
+			winners = append(winners, standing.Player)
+		}
+	}
+
+	return winners
+}
@@ -0,0 +1,129 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maria-mz/bash-battle-server/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func newScoredPlayer(name string, scores ...Score) *Player {
+	player := NewPlayer(name)
+
+	for round, score := range scores {
+		score.Round = round + 1
+		player.SetRoundScore(score)
+	}
+
+	return player
+}
+
+type leaderboardTest struct {
+	name      string
+	players   []*Player
+	standings []Standing
+	winners   []string
+}
+
+var leaderboardTests = []leaderboardTest{
+	{
+		name:      "no players",
+		players:   []*Player{},
+		standings: []Standing{},
+	},
+	{
+		name: "ranked by points",
+		players: []*Player{
+			newScoredPlayer("ana", Score{Points: 10}),
+			newScoredPlayer("ben", Score{Points: 30}),
+			newScoredPlayer("cleo", Score{Points: 20}),
+		},
+		standings: []Standing{
+			{Rank: 1, Player: "ben", Points: 30},
+			{Rank: 2, Player: "cleo", Points: 20},
+			{Rank: 3, Player: "ana", Points: 10},
+		},
+		winners: []string{"ben"},
+	},
+	{
+		name: "tie broken by rounds won",
+		players: []*Player{
+			newScoredPlayer("ana", Score{Points: 10, Win: true}),
+			newScoredPlayer("ben", Score{Points: 5, Win: true}, Score{Points: 5, Win: true}),
+		},
+		standings: []Standing{
+			{Rank: 1, Player: "ben", Points: 10, RoundsWon: 2},
+			{Rank: 2, Player: "ana", Points: 10, RoundsWon: 1},
+		},
+		winners: []string{"ben"},
+	},
+	{
+		name: "tie broken by solve time",
+		players: []*Player{
+			newScoredPlayer("ana", Score{Points: 10, Win: true, SolveTime: 20 * time.Second}),
+			newScoredPlayer("ben", Score{Points: 10, Win: true, SolveTime: 10 * time.Second}),
+		},
+		standings: []Standing{
+			{Rank: 1, Player: "ben", Points: 10, RoundsWon: 1, SolveTime: 10 * time.Second},
+			{Rank: 2, Player: "ana", Points: 10, RoundsWon: 1, SolveTime: 20 * time.Second},
+		},
+		winners: []string{"ben"},
+	},
+	{
+		name: "full tie shares rank",
+		players: []*Player{
+			newScoredPlayer("cleo", Score{Points: 10}),
+			newScoredPlayer("ben", Score{Points: 10}),
+			newScoredPlayer("ana", Score{Points: 5}),
+			newScoredPlayer("dev", Score{Points: 20}),
+		},
+		standings: []Standing{
+			{Rank: 1, Player: "dev", Points: 20},
+			{Rank: 2, Player: "ben", Points: 10},
+			{Rank: 2, Player: "cleo", Points: 10},
+			{Rank: 4, Player: "ana", Points: 5},
+		},
+		winners: []string{"dev"},
+	},
+	{
+		name: "tied winners",
+		players: []*Player{
+			newScoredPlayer("ben", Score{Points: 10}),
+			newScoredPlayer("ana", Score{Points: 10}),
+		},
+		standings: []Standing{
+			{Rank: 1, Player: "ana", Points: 10},
+			{Rank: 1, Player: "ben", Points: 10},
+		},
+		winners: []string{"ana", "ben"},
+	},
+}
+
+func TestBuildLeaderboard(t *testing.T) {
+	for _, test := range leaderboardTests {
+		t.Run(test.name, func(t *testing.T) {
+			standings := BuildLeaderboard(test.players)
+
+			assert.Equal(t, test.standings, standings)
+			assert.Equal(t, test.winners, Winners(standings))
+		})
+	}
+}
+
+func TestNewGameResult(t *testing.T) {
+	data := NewGameData(config.GameConfig{Rounds: 2}, map[int]Challenge{
+		0: {ID: "first"},
+		1: {ID: "second"},
+	})
+
+	standings := []Standing{{Rank: 1, Player: "ana", Points: 10}}
+	finishedAt := time.Now()
+
+	result := NewGameResult(data, standings, finishedAt)
+
+	assert.Equal(t, []string{"first", "second"}, result.Challenges)
+	assert.Equal(t, standings, result.Standings)
+	assert.Equal(t, []string{"ana"}, result.Winners)
+	assert.Equal(t, finishedAt, result.FinishedAt)
+}
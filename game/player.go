@@ -2,19 +2,26 @@ package game
 
 import (
 	"fmt"
+	"time"
 
 	pb "github.com/maria-mz/bash-battle-proto/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 type Score struct {
-	Round   int
-	Win     bool
-	CmdUsed string
+	Round         int
+	Win           bool
+	CmdUsed       string
+	WrongAttempts int
+	SolveTime     time.Duration // From round start to the winning submission
+	Points        int           // Set by the game's ScoringStrategy when the round ends
+	Forfeit       bool          // Didn't submit anything for the round
 }
 
 type Player struct {
 	Name   string
 	Scores map[int]Score
+	Absent bool // Disconnected after the game started
 }
 
 func NewPlayer(name string) *Player {
@@ -24,21 +31,80 @@ func NewPlayer(name string) *Player {
 	}
 }
 
+// Clone returns a copy of the player that doesn't share its scores.
+func (player *Player) Clone() *Player {
+	clone := *player
+	clone.Scores = make(map[int]Score, len(player.Scores))
+
+	for round, score := range player.Scores {
+		clone.Scores[round] = score
+	}
+
+	return &clone
+}
+
 func (player *Player) SetRoundScore(score Score) {
 	player.Scores[score.Round] = score
 }
 
+// TotalPoints returns the points the player got across all rounds.
+func (player *Player) TotalPoints() int {
+	total := 0
+
+	for _, score := range player.Scores {
+		total += score.Points
+	}
+
+	return total
+}
+
+// RoundsWon returns the number of rounds the player won.
+func (player *Player) RoundsWon() int {
+	won := 0
+
+	for _, score := range player.Scores {
+		if score.Win {
+			won++
+		}
+	}
+
+	return won
+}
+
+// TotalSolveTime returns the time the player took to win rounds, summed.
+func (player *Player) TotalSolveTime() time.Duration {
+	var total time.Duration
+
+	for _, score := range player.Scores {
+		if score.Win {
+			total += score.SolveTime
+		}
+	}
+
+	return total
+}
+
 // Don't know how much I like this but it is what it is
 func (player *Player) ToProto() *pb.Player {
 	gameStats := &pb.GameStats{
-		RoundStats: make(map[int32]*pb.RoundStats),
+		RoundStats:  make(map[int32]*pb.RoundStats),
+		TotalPoints: int32(player.TotalPoints()),
+		RoundsWon:   int32(player.RoundsWon()),
 	}
 
 	for round, score := range player.Scores {
 		roundStats := &pb.RoundStats{
-			Won:     score.Win,
-			Command: score.CmdUsed,
+			Won:           score.Win,
+			Command:       score.CmdUsed,
+			WrongAttempts: int32(score.WrongAttempts),
+			Points:        int32(score.Points),
+			Forfeit:       score.Forfeit,
 		}
+
+		if score.Win {
+			roundStats.SolveTime = durationpb.New(score.SolveTime)
+		}
+
 		gameStats.RoundStats[int32(round)] = roundStats
 	}
 
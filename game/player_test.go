@@ -0,0 +1,36 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlayerToProto_Scores(t *testing.T) {
+	p := NewPlayer("player-1")
+
+	p.SetRoundScore(Score{
+		Round: 1, Win: true, CmdUsed: "sort input.txt", WrongAttempts: 2,
+		SolveTime: 12 * time.Second, Points: 70,
+	})
+	p.SetRoundScore(Score{Round: 2, Forfeit: true})
+
+	stats := p.ToProto().GetStats()
+
+	assert.Equal(t, int32(70), stats.GetTotalPoints())
+	assert.Equal(t, int32(1), stats.GetRoundsWon())
+
+	won := stats.GetRoundStats()[1]
+
+	assert.True(t, won.GetWon())
+	assert.Equal(t, "sort input.txt", won.GetCommand())
+	assert.Equal(t, int32(2), won.GetWrongAttempts())
+	assert.Equal(t, 12*time.Second, won.GetSolveTime().AsDuration())
+	assert.Equal(t, int32(70), won.GetPoints())
+
+	forfeit := stats.GetRoundStats()[2]
+
+	assert.True(t, forfeit.GetForfeit())
+	assert.Nil(t, forfeit.GetSolveTime()) // Only set for wins
+}
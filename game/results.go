@@ -0,0 +1,33 @@
+package game
+
+import (
+	"time"
+
+	"github.com/maria-mz/bash-battle-server/config"
+)
+
+// GameResult is the record of a finished game, kept after the game is reset
+// for a rematch.
+type GameResult struct {
+	Config     config.GameConfig
+	Challenges []string // Challenge IDs, in round order
+	Standings  []Standing
+	Winners    []string
+	FinishedAt time.Time
+}
+
+func NewGameResult(data *GameData, standings []Standing, finishedAt time.Time) GameResult {
+	challenges := make([]string, 0, len(data.Challenges))
+
+	for round := 0; round < len(data.Challenges); round++ {
+		challenges = append(challenges, data.Challenges[round].ID)
+	}
+
+	return GameResult{
+		Config:     data.Config,
+		Challenges: challenges,
+		Standings:  standings,
+		Winners:    Winners(standings),
+		FinishedAt: finishedAt,
+	}
+}
@@ -1,11 +1,13 @@
 package game
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
 	"time"
 
+	"github.com/maria-mz/bash-battle-server/clock"
 	"github.com/maria-mz/bash-battle-server/log"
 )
 
@@ -18,32 +20,63 @@ const (
 	// RoundStarted - Timer started for the current round.
 	RoundStarted
 
-	// RoundEnded - Timer expired for the current round.
+	// RoundEnded - Timer expired for the current round, or the round was
+	// ended early.
 	RoundEnded
 )
 
 var ErrNoRoundsLeft error = errors.New("there are no rounds left to play")
+var ErrRoundNotRunning error = errors.New("no round is running")
+var ErrRunnerStopped error = errors.New("runner is stopped")
 
 // GameRunner runs the rounds of a Bash Battle game.
+//
+// The countdown and round timers can be paused and resumed, a running
+// round can be ended early, and the runner can be stopped altogether.
 type GameRunner struct {
 	GameData *GameData
 	round    int
 	ch       chan<- RunnerEvent
-	mu       sync.Mutex
+	clock    clock.Clock
+
+	ctx    context.Context // Cancelled when the runner is stopped
+	cancel context.CancelFunc
+
+	running  bool               // A round's goroutine is running
+	closed   bool               // ch is closed
+	endRound context.CancelFunc // Ends the current round's timer, if running
+
+	// Timer of the current countdown or round. While paused, remaining
+	// holds the time that was left, otherwise endsAt is when it expires.
+	timing    bool
+	paused    bool
+	remaining time.Duration
+	endsAt    time.Time
+	changed   chan struct{} // Signals the timer was paused or resumed
+
+	mu sync.Mutex
 }
 
-func NewGameRunner(game *GameData) (*GameRunner, <-chan RunnerEvent) {
+func NewGameRunner(game *GameData, clk clock.Clock) (*GameRunner, <-chan RunnerEvent) {
 	ch := make(chan RunnerEvent)
+	ctx, cancel := context.WithCancel(context.Background())
 
 	runner := &GameRunner{
 		GameData: game,
 		ch:       ch,
+		clock:    clk,
+		ctx:      ctx,
+		cancel:   cancel,
+		changed:  make(chan struct{}, 1),
 	}
 
 	return runner, ch
 }
 
 func (runner *GameRunner) GetCurrentRound() int {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+
 	return runner.round
 }
 
@@ -51,42 +84,241 @@ func (runner *GameRunner) RunRound() error {
 	runner.mu.Lock()
 	defer runner.mu.Unlock()
 
-	if runner.IsFinalRound() {
+	if runner.ctx.Err() != nil {
+		return ErrRunnerStopped
+	}
+
+	if runner.isFinalRound() {
 		return ErrNoRoundsLeft
 	}
 
 	runner.round++
+	runner.running = true
+
+	go runner.run(runner.round)
+
+	return nil
+}
+
+func (runner *GameRunner) run(round int) {
+	defer runner.done(round)
+
+	if !runner.send(CountingDown) {
+		return
+	}
+
+	log.Logger.Info(fmt.Sprintf("Counting down to round %d", round))
+	runner.wait(runner.ctx, runner.GameData.GetCountdownDuration())
+
+	// Can be ended as soon as RoundStarted is received
+	ctx, endRound := context.WithCancel(runner.ctx)
+	runner.setEndRound(endRound)
+	defer endRound()
+
+	if !runner.send(RoundStarted) {
+		return
+	}
+
+	log.Logger.Info(fmt.Sprintf("Started round %d", round))
+	runner.wait(ctx, runner.GameData.GetRoundDuration())
+	runner.setEndRound(nil)
+
+	if !runner.send(RoundEnded) {
+		return
+	}
+
+	log.Logger.Info(fmt.Sprintf("Ended round %d", round))
+}
+
+// done closes the RunnerEvent channel after the final round, or once the
+// runner is stopped.
+func (runner *GameRunner) done(round int) {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+
+	runner.running = false
+
+	if round == runner.GameData.Config.Rounds || runner.ctx.Err() != nil {
+		runner.closeEvents()
+	}
+}
+
+func (runner *GameRunner) closeEvents() {
+	if runner.closed {
+		return
+	}
+
+	runner.closed = true
+	close(runner.ch)
+
+	log.Logger.Info("Runner done. Closed RunnerEvent channel")
+}
+
+// send sends an event, unless the runner is stopped first.
+func (runner *GameRunner) send(event RunnerEvent) bool {
+	if runner.ctx.Err() != nil {
+		return false
+	}
+
+	select {
+	case runner.ch <- event:
+		return true
+	case <-runner.ctx.Done():
+		return false
+	}
+}
+
+func (runner *GameRunner) setEndRound(endRound context.CancelFunc) {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+
+	runner.endRound = endRound
+}
+
+// EndRound ends the running round now instead of when its timer expires.
+func (runner *GameRunner) EndRound() error {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
 
-	go runner.run()
+	if runner.endRound == nil {
+		return ErrRoundNotRunning
+	}
+
+	runner.endRound()
+	runner.endRound = nil
 
 	return nil
 }
 
-func (runner *GameRunner) run() {
-	runner.ch <- CountingDown
+// Pause stops the countdown or round timer until Resume is called. If
+// nothing is running, the next timer starts out paused.
+func (runner *GameRunner) Pause() {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+
+	if runner.paused {
+		return
+	}
+
+	runner.paused = true
 
-	log.Logger.Info(fmt.Sprintf("Counting down to round %d", runner.round))
-	runner.wait(runner.GameData.GetCountdownDuration())
+	if runner.timing {
+		runner.remaining = max(runner.clock.Until(runner.endsAt), 0)
+	}
+
+	runner.notify()
+}
+
+// Resume restarts a paused timer, and returns when it now expires. The
+// time is zero if no timer is running.
+func (runner *GameRunner) Resume() time.Time {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
 
-	runner.ch <- RoundStarted
+	if !runner.timing {
+		runner.paused = false
+		return time.Time{}
+	}
+
+	if runner.paused {
+		runner.paused = false
+		runner.endsAt = runner.clock.Now().Add(runner.remaining)
+		runner.notify()
+	}
+
+	return runner.endsAt
+}
+
+func (runner *GameRunner) IsPaused() bool {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+
+	return runner.paused
+}
+
+// Stop stops the runner. A running round ends without sending any more
+// events, and the RunnerEvent channel is closed.
+func (runner *GameRunner) Stop() {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
 
-	log.Logger.Info(fmt.Sprintf("Started round %d", runner.round))
-	runner.wait(runner.GameData.GetRoundDuration())
+	runner.cancel()
 
-	runner.ch <- RoundEnded
-	log.Logger.Info(fmt.Sprintf("Ended round %d", runner.round))
+	if !runner.running {
+		runner.closeEvents()
+	}
+}
 
-	if runner.IsFinalRound() {
-		close(runner.ch)
-		log.Logger.Info("Final round done. Closed RunnerEvent channel")
+func (runner *GameRunner) notify() {
+	select {
+	case runner.changed <- struct{}{}:
+	default:
 	}
 }
 
 func (runner *GameRunner) IsFinalRound() bool {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+
+	return runner.isFinalRound()
+}
+
+func (runner *GameRunner) isFinalRound() bool {
 	return runner.round == runner.GameData.Config.Rounds
 }
 
-func (runner *GameRunner) wait(duration time.Duration) {
-	timer := time.NewTimer(duration)
-	<-timer.C
+// wait blocks until the timer expires or ctx is done. The timer doesn't
+// run while the runner is paused.
+func (runner *GameRunner) wait(ctx context.Context, duration time.Duration) {
+	runner.startTimer(duration)
+	defer runner.stopTimer()
+
+	for {
+		runner.mu.Lock()
+		paused, endsAt := runner.paused, runner.endsAt
+		runner.mu.Unlock()
+
+		if paused {
+			select {
+			case <-ctx.Done():
+				return
+			case <-runner.changed:
+				continue
+			}
+		}
+
+		timer := runner.clock.NewTimer(runner.clock.Until(endsAt))
+
+		select {
+		case <-timer.C():
+			if !runner.IsPaused() { // Unless paused just as it expired
+				return
+			}
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-runner.changed:
+			timer.Stop()
+		}
+	}
+}
+
+func (runner *GameRunner) startTimer(duration time.Duration) {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+
+	runner.timing = true
+
+	if runner.paused {
+		runner.remaining = duration
+	} else {
+		runner.endsAt = runner.clock.Now().Add(duration)
+	}
+}
+
+func (runner *GameRunner) stopTimer() {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+
+	runner.timing = false
 }
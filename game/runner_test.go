@@ -2,7 +2,9 @@ package game
 
 import (
 	"testing"
+	"time"
 
+	"github.com/maria-mz/bash-battle-server/clock"
 	"github.com/maria-mz/bash-battle-server/config"
 	"github.com/maria-mz/bash-battle-server/log"
 	"github.com/stretchr/testify/assert"
@@ -10,8 +12,8 @@ import (
 
 var testConfig = config.GameConfig{
 	Rounds:            2,
-	RoundDuration:     1, // seconds
-	CountdownDuration: 1, // seconds
+	RoundDuration:     60, // seconds
+	CountdownDuration: 5,  // seconds
 }
 
 func TestMain(m *testing.M) {
@@ -19,9 +21,31 @@ func TestMain(m *testing.M) {
 	m.Run()
 }
 
+// newTestRunner returns a runner on a fake clock.
+func newTestRunner(config config.GameConfig) (*GameRunner, <-chan RunnerEvent, *clock.Fake) {
+	clk := clock.NewFake(time.Now())
+	runner, ch := NewGameRunner(NewGameData(config, map[int]Challenge{}), clk)
+
+	return runner, ch, clk
+}
+
+// advance moves the clock along once the runner is waiting on its timer.
+func advance(clk *clock.Fake, d time.Duration) {
+	clk.BlockUntil(1)
+	clk.Advance(d)
+}
+
+// assertNoEvent checks no event is sent for a while, in real time.
+func assertNoEvent(t *testing.T, ch <-chan RunnerEvent) {
+	select {
+	case event := <-ch:
+		t.Errorf("unexpected event %d", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
 func TestNewGameRunner(t *testing.T) {
-	data := NewGameData(testConfig)
-	runner, _ := NewGameRunner(data)
+	runner, _, _ := newTestRunner(testConfig)
 
 	assert.NotNil(t, runner)
 	assert.NotNil(t, runner.GameData)
@@ -30,33 +54,106 @@ func TestNewGameRunner(t *testing.T) {
 }
 
 func TestRunRound(t *testing.T) {
-	data := NewGameData(testConfig)
-	runner, ch := NewGameRunner(data)
+	runner, ch, clk := newTestRunner(testConfig)
+
+	for round := 1; round <= testConfig.Rounds; round++ {
+		err := runner.RunRound()
+		assert.Nil(t, err)
+
+		assert.Equal(t, CountingDown, <-ch)
+		assert.Equal(t, round, runner.GetCurrentRound())
+
+		advance(clk, 5*time.Second)
+
+		assert.Equal(t, RoundStarted, <-ch)
+		assert.Equal(t, round, runner.GetCurrentRound())
+
+		// Not over until the round's full duration has passed
+		advance(clk, 59*time.Second)
+		assertNoEvent(t, ch)
+		clk.Advance(time.Second)
+
+		assert.Equal(t, RoundEnded, <-ch)
+		assert.Equal(t, round, runner.GetCurrentRound())
+	}
+
+	// Channel is closed after the final round
+	_, ok := <-ch
+	assert.False(t, ok)
 
-	// first round - ok
 	err := runner.RunRound()
-	assert.Nil(t, err)
+	assert.NotNil(t, err)
+	assert.Equal(t, err, ErrNoRoundsLeft)
+}
+
+func TestEndRound(t *testing.T) {
+	runner, ch, clk := newTestRunner(testConfig)
+
+	assert.ErrorIs(t, runner.EndRound(), ErrRoundNotRunning)
+
+	runner.RunRound()
 
 	assert.Equal(t, CountingDown, <-ch)
-	assert.Equal(t, 1, runner.GetCurrentRound())
+	assert.ErrorIs(t, runner.EndRound(), ErrRoundNotRunning)
+
+	advance(clk, 5*time.Second)
+
 	assert.Equal(t, RoundStarted, <-ch)
-	assert.Equal(t, 1, runner.GetCurrentRound())
+	assert.Nil(t, runner.EndRound())
 	assert.Equal(t, RoundEnded, <-ch)
-	assert.Equal(t, 1, runner.GetCurrentRound())
+	assert.ErrorIs(t, runner.EndRound(), ErrRoundNotRunning)
+}
+
+func TestPauseResume(t *testing.T) {
+	runner, ch, clk := newTestRunner(testConfig)
 
-	// second (and last) round - ok
-	err = runner.RunRound()
-	assert.Nil(t, err)
+	runner.RunRound()
 
 	assert.Equal(t, CountingDown, <-ch)
-	assert.Equal(t, 2, runner.GetCurrentRound())
+
+	advance(clk, 2*time.Second)
+	runner.Pause()
+	assert.True(t, runner.IsPaused())
+
+	// The countdown doesn't run while paused
+	clk.Advance(time.Hour)
+	assertNoEvent(t, ch)
+
+	endsAt := runner.Resume()
+
+	assert.False(t, runner.IsPaused())
+	assert.Equal(t, clk.Now().Add(3*time.Second), endsAt)
+
+	advance(clk, 3*time.Second)
+
 	assert.Equal(t, RoundStarted, <-ch)
-	assert.Equal(t, 2, runner.GetCurrentRound())
+
+	// Pausing right as the round starts holds the whole round
+	runner.Pause()
+	clk.Advance(time.Hour)
+	assertNoEvent(t, ch)
+
+	runner.Resume()
+	advance(clk, 60*time.Second)
+
 	assert.Equal(t, RoundEnded, <-ch)
-	assert.Equal(t, 2, runner.GetCurrentRound())
+}
 
-	// error
-	err = runner.RunRound()
-	assert.NotNil(t, err)
-	assert.Equal(t, err, ErrNoRoundsLeft)
+func TestStop(t *testing.T) {
+	runner, ch, _ := newTestRunner(testConfig)
+
+	runner.RunRound()
+
+	assert.Equal(t, CountingDown, <-ch)
+	runner.Stop()
+
+	// No more events, and the channel gets closed
+	select {
+	case _, ok := <-ch:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("RunnerEvent channel not closed")
+	}
+
+	assert.ErrorIs(t, runner.RunRound(), ErrRunnerStopped)
 }
@@ -0,0 +1,482 @@
+// Package sandbox runs untrusted bash one-liners, such as player
+// submissions, with as little access to the host as plain Linux allows.
+//
+// Every command runs in a fresh temporary working directory, in a
+// restricted bash (bash -r) whose PATH only holds an allowlist of binaries,
+// with a clean environment, rlimits on CPU time, memory, file size and
+// processes, a wall-clock timeout and capped output. It runs in its own
+// user, mount, PID and network namespaces, without any capabilities: its
+// root only holds the working directory, the allowed binaries, the host's
+// system directories (read-only, for the binaries' libraries) and a /proc
+// showing only its own processes. Nothing else of the host's filesystem,
+// such as /etc or the server's config, can be reached.
+//
+// The allowlist narrows what a command can run directly, but it is not a
+// security boundary by itself: some allowed tools can still start other
+// programs (awk's system(), for one). The namespaces, private root and
+// rlimits are what contain a command.
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var ErrIsolationUnavailable = errors.New("namespaces to isolate commands in are not available on this host")
+var ErrInvalidFileName = errors.New("file name must be a plain file name")
+
+// DefaultAllowedBinaries are the tools commands may use by default.
+var DefaultAllowedBinaries = []string{
+	"awk", "base64", "basename", "cat", "column", "comm", "cut", "diff",
+	"dirname", "expand", "expr", "fmt", "fold", "grep", "head", "join", "jq",
+	"ls", "md5sum", "nl", "od", "paste", "rev", "sed", "seq", "sha256sum",
+	"sleep", "sort", "tac", "tail", "tee", "tr", "unexpand", "uniq", "wc",
+}
+
+// Config sets the limits commands run under.
+type Config struct {
+	Timeout         time.Duration // Wall-clock time limit
+	CPUTime         time.Duration // CPU time limit, rounded up to the second
+	MaxOutputBytes  int           // Cap on captured stdout, and on stderr
+	MaxFileBytes    int64         // Largest file a command may write
+	MaxMemoryBytes  int64         // Virtual memory limit
+	MaxProcesses    int           // Process limit, counted within the sandbox when isolated
+	AllowedBinaries []string      // Names of the binaries commands may run
+
+	// Run commands in their own namespaces, with a private root. Only
+	// turn off for trusted commands: without it a command can read any
+	// file the server can
+	Isolate bool
+}
+
+// DefaultConfig returns limits suitable for checking challenge answers.
+func DefaultConfig() Config {
+	return Config{
+		Timeout:         2 * time.Second,
+		CPUTime:         1 * time.Second,
+		MaxOutputBytes:  64 << 10,
+		MaxFileBytes:    1 << 20,
+		MaxMemoryBytes:  256 << 20,
+		MaxProcesses:    64,
+		AllowedBinaries: DefaultAllowedBinaries,
+		Isolate:         true,
+	}
+}
+
+// Result is the outcome of running a command.
+type Result struct {
+	Stdout          []byte
+	Stderr          []byte
+	ExitCode        int // -1 if the command was killed by a signal
+	Duration        time.Duration
+	TimedOut        bool
+	OutputTruncated bool
+}
+
+// systemDirs are the host directories the binaries and their libraries
+// live in, mounted read-only into an isolated command's root (if they exist).
+var systemDirs = []string{"/usr", "/bin", "/lib", "/lib32", "/lib64", "/libx32"}
+
+// Paths inside the root of a command, which are also the directories in
+// the temporary root a command is set up in.
+const (
+	toolsDir   = "/tools" // The allowed binaries, the command's PATH
+	workDir    = "/work"
+	procDir    = "/proc"
+	oldRootDir = "/.host" // Where the host's root is unmounted from
+)
+
+var (
+	isolationOnce      sync.Once
+	isolationAvailable bool
+)
+
+// CanIsolate checks (once) if commands can be given their own namespaces,
+// which needs user namespaces (unprivileged, unless running as root).
+func CanIsolate() bool {
+	isolationOnce.Do(func() {
+		err := exec.Command(
+			"unshare", "--user", "--map-root-user", "--mount", "--pid", "--fork", "--net", "true",
+		).Run()
+		isolationAvailable = err == nil
+	})
+	return isolationAvailable
+}
+
+// Sandbox runs commands under a Config. It is safe for concurrent use.
+type Sandbox struct {
+	config   Config
+	bash     string
+	binaries map[string]string // name -> path on the host, symlinks resolved
+
+	// Used to isolate commands, see isolatedArgs
+	unshare    string
+	mount      string
+	umount     string
+	pivotRoot  string
+	setpriv    string
+	systemDirs []systemDir
+}
+
+// systemDir is a system directory of the host as it appears in the root of
+// an isolated command: either mounted, or a symlink like on the host.
+type systemDir struct {
+	path string
+	link string // Symlink target, if the directory is a symlink
+}
+
+// New creates a Sandbox. Allowed binaries that can't be found on the host
+// are left out.
+func New(config Config) (*Sandbox, error) {
+	bash, err := lookPath("bash")
+
+	if err != nil {
+		return nil, err
+	}
+
+	sb := &Sandbox{
+		config:   config,
+		bash:     bash,
+		binaries: make(map[string]string),
+	}
+
+	if config.Isolate {
+		if err := sb.setupIsolation(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range config.AllowedBinaries {
+		path, err := lookPath(name)
+		if err != nil || !sb.reachable(path) {
+			continue
+		}
+		sb.binaries[name] = path
+	}
+
+	return sb, nil
+}
+
+// setupIsolation finds the tools commands are isolated with, and the system
+// directories to mount into their root.
+func (sb *Sandbox) setupIsolation() error {
+	if !CanIsolate() {
+		return ErrIsolationUnavailable
+	}
+
+	for _, dir := range systemDirs {
+		info, err := os.Lstat(dir)
+
+		switch {
+		case err != nil:
+			continue
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(dir)
+			if err != nil {
+				return err
+			}
+			sb.systemDirs = append(sb.systemDirs, systemDir{path: dir, link: link})
+		case info.IsDir():
+			sb.systemDirs = append(sb.systemDirs, systemDir{path: dir})
+		}
+	}
+
+	tools := map[string]*string{
+		"unshare":    &sb.unshare,
+		"mount":      &sb.mount,
+		"umount":     &sb.umount,
+		"pivot_root": &sb.pivotRoot,
+		"setpriv":    &sb.setpriv,
+	}
+
+	for name, path := range tools {
+		found, err := lookPath(name)
+
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrIsolationUnavailable, err)
+		}
+
+		*path = found
+	}
+
+	// Run from inside the command's root
+	for _, path := range []string{sb.bash, sb.umount, sb.setpriv} {
+		if !sb.reachable(path) {
+			return fmt.Errorf("%w: %s is outside the system directories", ErrIsolationUnavailable, path)
+		}
+	}
+
+	return nil
+}
+
+// reachable checks if a command can run the binary at path (symlinks
+// resolved): isolated commands only see the system directories.
+func (sb *Sandbox) reachable(path string) bool {
+	if !sb.config.Isolate {
+		return true
+	}
+
+	for _, dir := range sb.systemDirs {
+		if dir.link == "" && strings.HasPrefix(path, dir.path+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lookPath finds a binary in PATH, resolving symlinks: a link may point
+// outside the system directories, into /etc/alternatives for one.
+func lookPath(name string) (string, error) {
+	path, err := exec.LookPath(name)
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.EvalSymlinks(path)
+}
+
+// Binaries returns the names of the binaries commands can run.
+func (sb *Sandbox) Binaries() []string {
+	names := make([]string, 0, len(sb.binaries))
+
+	for name := range sb.binaries {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// Run runs cmd in a fresh working directory holding the given files
+// (file name -> contents). A command failing or timing out is reported in
+// the Result; the error is only set if the command couldn't be run.
+func (sb *Sandbox) Run(ctx context.Context, cmd string, files map[string][]byte) (Result, error) {
+	var result Result
+
+	root, err := os.MkdirTemp("", "bash-battle-sandbox-")
+
+	if err != nil {
+		return result, err
+	}
+	defer os.RemoveAll(root)
+
+	if err := sb.setup(root, files); err != nil {
+		return result, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sb.config.Timeout)
+	defer cancel()
+
+	var proc *exec.Cmd
+
+	if sb.config.Isolate {
+		proc = exec.CommandContext(ctx, sb.unshare, sb.isolatedArgs(root, cmd)...)
+		proc.Dir = root
+		proc.Env = sb.env("")
+	} else {
+		proc = exec.CommandContext(ctx, sb.bash, sb.args(cmd)...)
+		proc.Dir = filepath.Join(root, workDir)
+		proc.Env = sb.env(root)
+	}
+
+	// Kill the whole process group on timeout, not just bash
+	proc.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	proc.Cancel = func() error {
+		return syscall.Kill(-proc.Process.Pid, syscall.SIGKILL)
+	}
+	proc.WaitDelay = 100 * time.Millisecond
+
+	stdout := &cappedBuffer{limit: sb.config.MaxOutputBytes}
+	stderr := &cappedBuffer{limit: sb.config.MaxOutputBytes}
+	proc.Stdout = stdout
+	proc.Stderr = stderr
+
+	start := time.Now()
+	err = proc.Run()
+	result.Duration = time.Since(start)
+
+	result.Stdout = stdout.Bytes()
+	result.Stderr = stderr.Bytes()
+	result.OutputTruncated = stdout.truncated || stderr.truncated
+	result.TimedOut = ctx.Err() == context.DeadlineExceeded
+
+	if proc.ProcessState != nil {
+		result.ExitCode = proc.ProcessState.ExitCode()
+	}
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) && !result.TimedOut {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// setup lays out the root a command runs in: the working directory holding
+// the files, the allowed binaries, and the mount points of an isolated root.
+func (sb *Sandbox) setup(root string, files map[string][]byte) error {
+	for _, dir := range []string{toolsDir, workDir, procDir, oldRootDir} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0o755); err != nil {
+			return err
+		}
+	}
+
+	for _, dir := range sb.systemDirs {
+		var err error
+
+		if dir.link != "" {
+			err = os.Symlink(dir.link, filepath.Join(root, dir.path))
+		} else {
+			err = os.Mkdir(filepath.Join(root, dir.path), 0o755)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for name, path := range sb.binaries {
+		if err := os.Symlink(path, filepath.Join(root, toolsDir, name)); err != nil {
+			return err
+		}
+	}
+
+	for name, data := range files {
+		if name != filepath.Base(name) || name == "." || name == ".." {
+			return fmt.Errorf("%w: %q", ErrInvalidFileName, name)
+		}
+
+		if err := os.WriteFile(filepath.Join(root, workDir, name), data, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// env is the environment of a command whose root is at root on the host
+// ("" once isolated, where it is the command's own root).
+func (sb *Sandbox) env(root string) []string {
+	return []string{
+		"PATH=" + root + toolsDir,
+		"HOME=" + root + workDir,
+		"LANG=C.UTF-8",
+		"LC_ALL=C.UTF-8",
+	}
+}
+
+// limits are the commands setting the rlimits.
+func (sb *Sandbox) limits() []string {
+	cpuSeconds := int(math.Ceil(sb.config.CPUTime.Seconds()))
+
+	return []string{
+		fmt.Sprintf("ulimit -t %d", cpuSeconds),
+		fmt.Sprintf("ulimit -f %d", sb.config.MaxFileBytes/512), // 512-byte blocks
+		fmt.Sprintf("ulimit -v %d", sb.config.MaxMemoryBytes/1024),
+		fmt.Sprintf("ulimit -u %d", sb.config.MaxProcesses),
+	}
+}
+
+// args builds the bash arguments: an outer shell sets the rlimits, then
+// replaces itself with a restricted shell running the command.
+func (sb *Sandbox) args(cmd string) []string {
+	script := strings.Join(sb.limits(), " && ") +
+		fmt.Sprintf(" && exec %s --noprofile --norc -r -c \"$1\"", shellQuote(sb.bash))
+
+	return []string{"--noprofile", "--norc", "-c", script, "sandbox", cmd}
+}
+
+// isolatedArgs builds the unshare arguments running cmd in its own user,
+// mount, PID and network namespaces. As root of the new user namespace, an
+// outer shell turns root into a read-only root of its own, mounting into it
+// the working directory (writable), the system directories (read-only) and
+// a /proc of the new PID namespace. It pivots into it and unmounts the
+// host's root, sets the rlimits, then drops every capability and replaces
+// itself with a restricted shell running the command.
+//
+// Process limits are counted per user namespace, so the command's limit
+// isn't shared with the server's other processes.
+func (sb *Sandbox) isolatedArgs(root string, cmd string) []string {
+	in := func(path string) string {
+		return shellQuote(filepath.Join(root, path))
+	}
+
+	script := []string{
+		"set -e",
+		fmt.Sprintf("%s --bind %s %s", sb.mount, in("/"), in("/")),
+		fmt.Sprintf("%s --bind %s %s", sb.mount, in(workDir), in(workDir)),
+	}
+
+	for _, dir := range sb.systemDirs {
+		if dir.link != "" {
+			continue
+		}
+
+		script = append(script,
+			fmt.Sprintf("%s --rbind %s %s", sb.mount, shellQuote(dir.path), in(dir.path)),
+			fmt.Sprintf("%s -o remount,bind,ro %s", sb.mount, in(dir.path)),
+		)
+	}
+
+	script = append(script,
+		fmt.Sprintf("%s -t proc -o nosuid,nodev,noexec proc %s", sb.mount, in(procDir)),
+		fmt.Sprintf("%s -o remount,bind,ro %s", sb.mount, in("/")),
+		"cd "+in("/"),
+		fmt.Sprintf("%s . %s", sb.pivotRoot, shellQuote("."+oldRootDir)),
+		fmt.Sprintf("%s -l %s", sb.umount, oldRootDir),
+		"cd "+workDir,
+	)
+
+	script = append(script, sb.limits()...)
+	script = append(script, fmt.Sprintf(
+		"exec %s --bounding-set=-all --inh-caps=-all --no-new-privs %s --noprofile --norc -r -c \"$1\"",
+		sb.setpriv, shellQuote(sb.bash),
+	))
+
+	return []string{
+		"--user", "--map-root-user", "--mount", "--pid", "--fork", "--kill-child", "--net",
+		sb.bash, "--noprofile", "--norc", "-c", strings.Join(script, "\n"), "sandbox", cmd,
+	}
+}
+
+// shellQuote quotes s as a single shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// cappedBuffer keeps the first limit bytes written to it and discards the
+// rest, so a command can't make us buffer unbounded output.
+type cappedBuffer struct {
+	buf       bytes.Buffer // Not embedded, io.Copy would use its ReadFrom
+	limit     int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	room := b.limit - b.buf.Len()
+
+	if len(p) > room {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+
+	return b.buf.Write(p)
+}
+
+func (b *cappedBuffer) Bytes() []byte {
+	return b.buf.Bytes()
+}
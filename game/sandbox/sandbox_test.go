@@ -0,0 +1,219 @@
+package sandbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSandbox(t *testing.T, configure func(*Config)) *Sandbox {
+	config := DefaultConfig()
+	config.Timeout = 500 * time.Millisecond
+	config.Isolate = CanIsolate() // The limits can be tested either way
+
+	if configure != nil {
+		configure(&config)
+	}
+
+	sb, err := New(config)
+	assert.Nil(t, err)
+
+	return sb
+}
+
+func TestRun_Ok(t *testing.T) {
+	sb := newTestSandbox(t, nil)
+
+	files := map[string][]byte{"input.txt": []byte("b\na\n")}
+
+	result, err := sb.Run(context.Background(), "sort input.txt; echo oops >&2", files)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "a\nb\n", string(result.Stdout))
+	assert.Equal(t, "oops\n", string(result.Stderr))
+	assert.Equal(t, 0, result.ExitCode)
+	assert.False(t, result.TimedOut)
+	assert.False(t, result.OutputTruncated)
+	assert.NotZero(t, result.Duration)
+}
+
+func TestRun_ExitCode(t *testing.T) {
+	sb := newTestSandbox(t, nil)
+
+	result, err := sb.Run(context.Background(), "exit 7", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 7, result.ExitCode)
+}
+
+func TestRun_Timeout(t *testing.T) {
+	sb := newTestSandbox(t, func(config *Config) {
+		config.Timeout = 200 * time.Millisecond
+	})
+
+	start := time.Now()
+	result, err := sb.Run(context.Background(), "sleep 10 | cat", nil)
+
+	assert.Nil(t, err)
+	assert.True(t, result.TimedOut)
+	assert.Equal(t, -1, result.ExitCode)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestRun_CPUTimeLimit(t *testing.T) {
+	sb := newTestSandbox(t, func(config *Config) {
+		config.Timeout = 5 * time.Second
+		config.CPUTime = 1 * time.Second
+	})
+
+	result, err := sb.Run(context.Background(), "while true; do :; done", nil)
+
+	assert.Nil(t, err)
+	assert.False(t, result.TimedOut)
+	assert.NotEqual(t, 0, result.ExitCode)
+	assert.Less(t, result.Duration, 4*time.Second)
+}
+
+func TestRun_OutputCap(t *testing.T) {
+	sb := newTestSandbox(t, func(config *Config) {
+		config.MaxOutputBytes = 10
+	})
+
+	result, err := sb.Run(context.Background(), "seq 1 1000", nil)
+
+	assert.Nil(t, err)
+	assert.True(t, result.OutputTruncated)
+	assert.Equal(t, "1\n2\n3\n4\n5\n", string(result.Stdout))
+}
+
+func TestRun_CleanEnvironment(t *testing.T) {
+	t.Setenv("BASH_BATTLE_SECRET", "secret")
+
+	sb := newTestSandbox(t, nil)
+
+	result, err := sb.Run(context.Background(), "echo \"[$BASH_BATTLE_SECRET]\"", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "[]\n", string(result.Stdout))
+}
+
+func TestRun_Allowlist(t *testing.T) {
+	sb := newTestSandbox(t, func(config *Config) {
+		config.AllowedBinaries = []string{"cat"}
+	})
+
+	tests := map[string]string{
+		"not allowed":        "sort input.txt",
+		"absolute path":      "/usr/bin/sort input.txt",
+		"change PATH":        "PATH=/usr/bin:/bin sort input.txt",
+		"write to file":      "cat input.txt > copy.txt",
+		"leave working dir":  "cd / && cat input.txt",
+		"exec another shell": "exec /bin/sh -c 'sort input.txt'",
+	}
+
+	files := map[string][]byte{"input.txt": []byte("b\na\n")}
+
+	for name, cmd := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := sb.Run(context.Background(), cmd, files)
+
+			assert.Nil(t, err)
+			assert.NotEqual(t, 0, result.ExitCode)
+			assert.NotEqual(t, "a\nb\n", string(result.Stdout))
+		})
+	}
+
+	result, err := sb.Run(context.Background(), "cat input.txt", files)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "b\na\n", string(result.Stdout))
+}
+
+func TestRun_WorkingDirIsFresh(t *testing.T) {
+	sb := newTestSandbox(t, nil)
+
+	result, err := sb.Run(context.Background(), "ls", map[string][]byte{"input.txt": nil})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "input.txt\n", string(result.Stdout))
+}
+
+func TestRun_ErrInvalidFileName(t *testing.T) {
+	sb := newTestSandbox(t, nil)
+
+	_, err := sb.Run(context.Background(), "true", map[string][]byte{"../escape.txt": nil})
+
+	assert.ErrorIs(t, err, ErrInvalidFileName)
+}
+
+func TestRun_NoNetwork(t *testing.T) {
+	if !CanIsolate() {
+		t.Skip("namespaces are not available")
+	}
+
+	sb := newTestSandbox(t, func(config *Config) {
+		config.AllowedBinaries = append(config.AllowedBinaries, "cut", "tail", "tr")
+	})
+
+	// Only the loopback interface exists in an isolated network namespace
+	result, err := sb.Run(context.Background(), "tail -n +3 /proc/net/dev | cut -d: -f1 | tr -d ' '", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "lo\n", string(result.Stdout))
+}
+
+func TestRun_PrivateRoot(t *testing.T) {
+	if !CanIsolate() {
+		t.Skip("namespaces are not available")
+	}
+
+	sb := newTestSandbox(t, nil)
+
+	// Nothing of the host outside the system directories
+	result, err := sb.Run(context.Background(), "cat /etc/passwd", nil)
+
+	assert.Nil(t, err)
+	assert.NotEqual(t, 0, result.ExitCode)
+	assert.Empty(t, result.Stdout)
+
+	// Nor its processes, such as the server and its command line
+	result, err = sb.Run(context.Background(), "cat /proc/[0-9]*/cmdline", nil)
+
+	assert.Nil(t, err)
+	assert.NotContains(t, string(result.Stdout), "sandbox.test")
+
+	// The system directories can't be written to
+	result, err = sb.Run(context.Background(), "cat input.txt > /usr/bin/input.txt", map[string][]byte{"input.txt": nil})
+
+	assert.Nil(t, err)
+	assert.NotEqual(t, 0, result.ExitCode)
+}
+
+func TestRun_ProcessLimitPerSandbox(t *testing.T) {
+	if !CanIsolate() {
+		t.Skip("namespaces are not available")
+	}
+
+	// Counted within the sandbox, not against every process of the server's
+	// user
+	sb := newTestSandbox(t, func(config *Config) {
+		config.MaxProcesses = 8
+	})
+
+	result, err := sb.Run(context.Background(), "echo b a | tr ' ' '\\n' | sort | head -n 1", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "a\n", string(result.Stdout))
+}
+
+func TestNew_ErrIsolationUnavailable(t *testing.T) {
+	if CanIsolate() {
+		t.Skip("namespaces are available")
+	}
+
+	_, err := New(Config{Isolate: true})
+
+	assert.ErrorIs(t, err, ErrIsolationUnavailable)
+}
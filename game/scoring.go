@@ -0,0 +1,143 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const DefaultScoring = "standard"
+
+// RoundResult is what a scoring strategy needs to know about how a player
+// did in a round.
+type RoundResult struct {
+	Score         Score
+	RoundDuration time.Duration
+
+	// Length of the shortest winning command of the round, across all
+	// players. 0 if nobody won the round.
+	ShortestCmdLength int
+}
+
+// ScoringStrategy decides how many points a player gets for a round.
+type ScoringStrategy interface {
+	Points(result RoundResult) int
+}
+
+// WinLossScoring gives 1 point for each round won, and nothing else.
+type WinLossScoring struct{}
+
+func (WinLossScoring) Points(result RoundResult) int {
+	if result.Score.Win {
+		return 1
+	}
+	return 0
+}
+
+// StandardScoring rewards solving a round, solving it fast and solving it
+// with the shortest command, and takes points off for wrong attempts.
+// A round is never worth less than 0 points.
+type StandardScoring struct {
+	SolvePoints         int
+	MaxSpeedBonus       int // Scaled down linearly to 0 over the round
+	ShortestCmdBonus    int
+	WrongAttemptPenalty int
+}
+
+func (scoring StandardScoring) Points(result RoundResult) int {
+	points := -scoring.WrongAttemptPenalty * result.Score.WrongAttempts
+
+	if result.Score.Win {
+		points += scoring.SolvePoints
+		points += scoring.speedBonus(result.Score.SolveTime, result.RoundDuration)
+
+		if CommandLength(result.Score.CmdUsed) == result.ShortestCmdLength {
+			points += scoring.ShortestCmdBonus
+		}
+	}
+
+	return max(points, 0)
+}
+
+func (scoring StandardScoring) speedBonus(solveTime time.Duration, roundDuration time.Duration) int {
+	if roundDuration <= 0 {
+		return 0
+	}
+
+	timeLeft := min(max(roundDuration-solveTime, 0), roundDuration)
+
+	return int(int64(scoring.MaxSpeedBonus) * int64(timeLeft) / int64(roundDuration))
+}
+
+var scoringStrategies = map[string]ScoringStrategy{
+	"winloss": WinLossScoring{},
+	"standard": StandardScoring{
+		SolvePoints:         100,
+		MaxSpeedBonus:       50,
+		ShortestCmdBonus:    25,
+		WrongAttemptPenalty: 10,
+	},
+}
+
+// NewScoringStrategy returns the scoring strategy with the given name
+// (GameConfig.Scoring). An empty name selects the default strategy.
+func NewScoringStrategy(name string) (ScoringStrategy, error) {
+	if name == "" {
+		name = DefaultScoring
+	}
+
+	strategy, ok := scoringStrategies[name]
+
+	if !ok {
+		return nil, fmt.Errorf("unknown scoring strategy %q", name)
+	}
+
+	return strategy, nil
+}
+
+// CommandLength is the length of a command, in characters, for scoring.
+// Surrounding whitespace doesn't count.
+func CommandLength(cmd string) int {
+	return utf8.RuneCountInString(strings.TrimSpace(cmd))
+}
+
+// ScoreRound sets the points of every player's score for the round.
+// Forfeited rounds are worth 0 points.
+func ScoreRound(strategy ScoringStrategy, players []*Player, round int, roundDuration time.Duration) {
+	shortest := 0
+
+	for _, player := range players {
+		score, ok := player.Scores[round]
+
+		if !ok || !score.Win {
+			continue
+		}
+
+		length := CommandLength(score.CmdUsed)
+
+		if shortest == 0 || length < shortest {
+			shortest = length
+		}
+	}
+
+	for _, player := range players {
+		score, ok := player.Scores[round]
+
+		if !ok {
+			continue
+		}
+
+		if score.Forfeit {
+			score.Points = 0
+		} else {
+			score.Points = strategy.Points(RoundResult{
+				Score:             score,
+				RoundDuration:     roundDuration,
+				ShortestCmdLength: shortest,
+			})
+		}
+
+		player.SetRoundScore(score)
+	}
+}
@@ -0,0 +1,148 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testRoundDuration = 100 * time.Second
+
+var testStandardScoring = StandardScoring{
+	SolvePoints:         100,
+	MaxSpeedBonus:       50,
+	ShortestCmdBonus:    25,
+	WrongAttemptPenalty: 10,
+}
+
+type pointsTest struct {
+	name     string
+	strategy ScoringStrategy
+	result   RoundResult
+	points   int
+}
+
+var pointsTests = []pointsTest{
+	{
+		name:     "winloss: won",
+		strategy: WinLossScoring{},
+		result:   RoundResult{Score: Score{Win: true, WrongAttempts: 3}},
+		points:   1,
+	},
+	{
+		name:     "winloss: lost",
+		strategy: WinLossScoring{},
+		result:   RoundResult{Score: Score{Win: false}},
+		points:   0,
+	},
+	{
+		name:     "standard: solved instantly with shortest command",
+		strategy: testStandardScoring,
+		result: RoundResult{
+			Score:             Score{Win: true, CmdUsed: "sort f", SolveTime: 0},
+			RoundDuration:     testRoundDuration,
+			ShortestCmdLength: 6,
+		},
+		points: 175,
+	},
+	{
+		name:     "standard: solved halfway",
+		strategy: testStandardScoring,
+		result: RoundResult{
+			Score:             Score{Win: true, CmdUsed: "sort -r f", SolveTime: 50 * time.Second},
+			RoundDuration:     testRoundDuration,
+			ShortestCmdLength: 6,
+		},
+		points: 125,
+	},
+	{
+		name:     "standard: solved after the round ended",
+		strategy: testStandardScoring,
+		result: RoundResult{
+			Score:             Score{Win: true, CmdUsed: "sort -r f", SolveTime: 120 * time.Second},
+			RoundDuration:     testRoundDuration,
+			ShortestCmdLength: 6,
+		},
+		points: 100,
+	},
+	{
+		name:     "standard: whitespace doesn't count towards length",
+		strategy: testStandardScoring,
+		result: RoundResult{
+			Score:             Score{Win: true, CmdUsed: "  sort f\n", SolveTime: testRoundDuration},
+			RoundDuration:     testRoundDuration,
+			ShortestCmdLength: 6,
+		},
+		points: 125,
+	},
+	{
+		name:     "standard: wrong attempts before solving",
+		strategy: testStandardScoring,
+		result: RoundResult{
+			Score:             Score{Win: true, CmdUsed: "sort -r f", SolveTime: testRoundDuration, WrongAttempts: 2},
+			RoundDuration:     testRoundDuration,
+			ShortestCmdLength: 6,
+		},
+		points: 80,
+	},
+	{
+		name:     "standard: not solved",
+		strategy: testStandardScoring,
+		result: RoundResult{
+			Score:         Score{Win: false, WrongAttempts: 4},
+			RoundDuration: testRoundDuration,
+		},
+		points: 0,
+	},
+}
+
+func TestPoints(t *testing.T) {
+	for _, test := range pointsTests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.points, test.strategy.Points(test.result))
+		})
+	}
+}
+
+func TestNewScoringStrategy(t *testing.T) {
+	strategy, err := NewScoringStrategy("")
+	assert.Nil(t, err)
+	assert.Equal(t, scoringStrategies[DefaultScoring], strategy)
+
+	strategy, err = NewScoringStrategy("winloss")
+	assert.Nil(t, err)
+	assert.Equal(t, WinLossScoring{}, strategy)
+
+	_, err = NewScoringStrategy("golf")
+	assert.NotNil(t, err)
+}
+
+func TestScoreRound(t *testing.T) {
+	p1 := NewPlayer("player-1")
+	p2 := NewPlayer("player-2")
+	p3 := NewPlayer("player-3")
+	p4 := NewPlayer("player-4") // Didn't submit anything
+	p5 := NewPlayer("player-5")
+
+	p1.SetRoundScore(Score{Round: 1, Win: true, CmdUsed: "sort f", SolveTime: testRoundDuration})
+	p2.SetRoundScore(Score{Round: 1, Win: true, CmdUsed: "sort -r f", SolveTime: testRoundDuration})
+	p3.SetRoundScore(Score{Round: 1, Win: false, CmdUsed: "ls", WrongAttempts: 1})
+	p5.SetRoundScore(Score{Round: 1, Forfeit: true})
+
+	// Earlier rounds keep their points
+	p1.SetRoundScore(Score{Round: 0, Win: true, Points: 42})
+
+	players := []*Player{p1, p2, p3, p4, p5}
+
+	ScoreRound(testStandardScoring, players, 1, testRoundDuration)
+
+	assert.Equal(t, 125, p1.Scores[1].Points) // Shortest command
+	assert.Equal(t, 100, p2.Scores[1].Points)
+	assert.Equal(t, 0, p3.Scores[1].Points) // Not a winner, shorter command doesn't matter
+	assert.NotContains(t, p4.Scores, 1)
+	assert.Equal(t, 0, p5.Scores[1].Points)
+
+	assert.Equal(t, 167, p1.TotalPoints())
+	assert.Equal(t, 100, p2.TotalPoints())
+}
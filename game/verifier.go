@@ -0,0 +1,79 @@
+package game
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/maria-mz/bash-battle-server/game/sandbox"
+)
+
+// How many commands a verifier runs at once. Every game on the server
+// shares one verifier, so this bounds them all together.
+const MaxConcurrentVerifications = 4
+
+var ErrVerifyTimeout = errors.New("command timed out")
+
+// Verifier checks player submissions by running the submitted command
+// against the challenge's input file and comparing what it prints with the
+// expected output. It is safe for concurrent use.
+type Verifier struct {
+	sandbox *sandbox.Sandbox
+	slots   chan struct{} // Bounds the commands running at once
+}
+
+func NewVerifier(config sandbox.Config) (*Verifier, error) {
+	sb, err := sandbox.New(config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Verifier{
+		sandbox: sb,
+		slots:   make(chan struct{}, MaxConcurrentVerifications),
+	}, nil
+}
+
+// Verify runs cmd in a sandbox holding a copy of the challenge's input
+// file, and reports whether its output matches the challenge's expected
+// output. Trailing newlines are ignored. Waits for a slot while
+// MaxConcurrentVerifications commands are already running.
+func (verifier *Verifier) Verify(challenge Challenge, cmd string) (bool, error) {
+	expected, err := os.ReadFile(string(challenge.OutputFile))
+
+	if err != nil {
+		return false, err
+	}
+
+	input, err := os.ReadFile(string(challenge.InputFile))
+
+	if err != nil {
+		return false, err
+	}
+
+	files := map[string][]byte{
+		filepath.Base(string(challenge.InputFile)): input,
+	}
+
+	verifier.slots <- struct{}{}
+	result, err := verifier.sandbox.Run(context.Background(), cmd, files)
+	<-verifier.slots
+
+	if err != nil {
+		return false, err
+	}
+
+	if result.TimedOut {
+		return false, fmt.Errorf("%w after %s", ErrVerifyTimeout, result.Duration)
+	}
+
+	if result.OutputTruncated {
+		return false, nil
+	}
+
+	return bytes.Equal(bytes.TrimRight(result.Stdout, "\n"), bytes.TrimRight(expected, "\n")), nil
+}
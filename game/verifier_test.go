@@ -0,0 +1,100 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maria-mz/bash-battle-server/game/sandbox"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestVerifier(t *testing.T) *Verifier {
+	config := sandbox.DefaultConfig()
+	config.Timeout = 500 * time.Millisecond
+
+	verifier, err := NewVerifier(config)
+	assert.Nil(t, err)
+
+	return verifier
+}
+
+type verifyTest struct {
+	name    string
+	cmd     string
+	won     bool
+	wantErr error
+}
+
+var verifyTests = []verifyTest{
+	{name: "correct command", cmd: "sort input.txt", won: true},
+	{name: "missing trailing newline", cmd: "printf 'a\\nb'", won: true},
+	{name: "wrong output", cmd: "cat input.txt", won: false},
+	{name: "failing command", cmd: "exit 3", won: false},
+	{name: "input path as seen by player", cmd: "sort ./input.txt", won: true},
+	{name: "endless output", cmd: "while true; do echo a; done", won: false, wantErr: ErrVerifyTimeout},
+	{name: "timeout", cmd: "sleep 5", won: false, wantErr: ErrVerifyTimeout},
+}
+
+func TestVerify(t *testing.T) {
+	challenge := newFileChallenge(t, "b\na\n", "a\nb\n")
+	verifier := newTestVerifier(t)
+
+	for _, test := range verifyTests {
+		t.Run(test.name, func(t *testing.T) {
+			won, err := verifier.Verify(challenge, test.cmd)
+
+			assert.Equal(t, test.won, won)
+			assert.ErrorIs(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestVerify_WaitsForSlot(t *testing.T) {
+	challenge := newFileChallenge(t, "b\na\n", "a\nb\n")
+	verifier := newTestVerifier(t)
+
+	// As if other games were verifying as many commands as allowed
+	for i := 0; i < MaxConcurrentVerifications; i++ {
+		verifier.slots <- struct{}{}
+	}
+
+	done := make(chan bool, 1)
+
+	go func() {
+		won, _ := verifier.Verify(challenge, "sort input.txt")
+		done <- won
+	}()
+
+	assert.Never(t, func() bool { return len(done) > 0 }, 200*time.Millisecond, 10*time.Millisecond)
+
+	<-verifier.slots
+
+	select {
+	case won := <-done:
+		assert.True(t, won)
+	case <-time.After(time.Second):
+		t.Fatal("still waiting once a slot was free")
+	}
+}
+
+func TestCheckSolutions(t *testing.T) {
+	right := newFileChallenge(t, "b\na\n", "a\nb\n")
+	right.ID = "right"
+	right.Solution = "sort input.txt"
+
+	wrong := newFileChallenge(t, "b\na\n", "a\nb\n")
+	wrong.ID = "wrong"
+	wrong.Solution = "cat input.txt"
+
+	noSolution := newFileChallenge(t, "b\na\n", "a\nb\n")
+
+	verifier := newTestVerifier(t)
+
+	assert.Nil(t, NewCatalog(right, noSolution).CheckSolutions(verifier))
+
+	err := NewCatalog(right, wrong, noSolution).CheckSolutions(verifier)
+
+	assert.ErrorIs(t, err, ErrWrongSolution)
+	assert.ErrorContains(t, err, `challenge "wrong"`)
+	assert.NotContains(t, err.Error(), `challenge "right"`)
+}
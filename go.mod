@@ -3,13 +3,17 @@ module github.com/maria-mz/bash-battle-server
 go 1.22.2
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/charmbracelet/lipgloss v0.10.0
 	github.com/charmbracelet/log v0.4.0
 	github.com/google/uuid v1.6.0
-	github.com/maria-mz/bash-battle-proto v0.0.0-20240623180313-5a2f693499c0
+	github.com/maria-mz/bash-battle-proto v0.0.0-00010101000000-000000000000
 	github.com/stretchr/testify v1.9.0
+	golang.org/x/text v0.14.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240227224415-6ceb2ff114de
 	google.golang.org/grpc v1.63.2
 	google.golang.org/protobuf v1.34.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -26,7 +30,7 @@ require (
 	golang.org/x/exp v0.0.0-20231006140011-7918f672742d // indirect
 	golang.org/x/net v0.23.0 // indirect
 	golang.org/x/sys v0.18.0 // indirect
-	golang.org/x/text v0.14.0 // indirect
-	google.golang.org/genproto/googleapis/rpc v0.0.0-20240227224415-6ceb2ff114de // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+// Until the changes in docs/proto.md are published
+replace github.com/maria-mz/bash-battle-proto => ./third_party/bash-battle-proto
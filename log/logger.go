@@ -24,3 +24,21 @@ func InitLogger() {
 	Logger.SetReportCaller(true)
 	Logger.SetLevel(log.InfoLevel)
 }
+
+// ParseLevel returns the log level with the given name: debug, info, warn,
+// error or fatal.
+func ParseLevel(name string) (log.Level, error) {
+	return log.ParseLevel(name)
+}
+
+// SetLevel sets the level of Logger by name, see ParseLevel.
+func SetLevel(name string) error {
+	level, err := ParseLevel(name)
+
+	if err != nil {
+		return err
+	}
+
+	Logger.SetLevel(level)
+	return nil
+}
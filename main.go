@@ -1,23 +1,39 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"io"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/maria-mz/bash-battle-server/clock"
 	"github.com/maria-mz/bash-battle-server/config"
+	"github.com/maria-mz/bash-battle-server/game"
+	"github.com/maria-mz/bash-battle-server/game/sandbox"
 	"github.com/maria-mz/bash-battle-server/log"
 	"github.com/maria-mz/bash-battle-server/service"
 )
 
-func handleSignals(service *service.Service) {
+func handleSignals(service *service.Service, watcher *config.Watcher) {
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		for range hup {
+			watcher.Reload()
+		}
+	}()
+
 	go func() {
 		sig := <-stop
 		log.Logger.Info("Shutting down server gracefully", "signal", sig)
 
+		watcher.Stop()
 		service.Shutdown()
 		os.Exit(0)
 	}()
@@ -26,19 +42,73 @@ func handleSignals(service *service.Service) {
 func main() {
 	log.InitLogger()
 
-	config, err := config.LoadConfig()
+	conf, path, err := config.Load(os.Args[1:], os.Environ(), os.Stderr)
+
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+
+	if err != nil {
+		log.Logger.Fatal("Failed to load server config", "path", path, "err", err)
+	}
+
+	conf.ApplyDefaults()
+
+	if err := conf.Validate(); err != nil {
+		log.Logger.Fatal("Invalid server config", "path", path, "err", err)
+	}
+
+	log.SetLevel(conf.LogLevel)
+	log.Logger.Info("Loaded server config", "path", path, "config", conf)
+
+	catalog, err := game.LoadCatalog(conf.ChallengesDir)
 
 	if err != nil {
-		log.Logger.Fatal("Failed to load server config", "err", err)
+		log.Logger.Fatal("Failed to load challenges", "err", err)
 	}
 
+	if err := catalog.CanServe(conf.GameConfig); err != nil {
+		log.Logger.Fatal("Challenge catalog cannot serve game config", "err", err)
+	}
+
+	// One verifier for every game, so they share its bound on commands
+	// running at once
+	verifier, err := game.NewVerifier(sandbox.DefaultConfig())
+
+	if err != nil {
+		log.Logger.Fatal("Failed to create submission verifier", "err", err)
+	}
+
+	if err := catalog.CheckSolutions(verifier); err != nil {
+		log.Logger.Fatal("Challenge solutions are wrong", "err", err)
+	}
+
+	log.Logger.Info("Loaded challenges", "count", catalog.Size())
+
 	log.Logger.Info(
-		"Configuring server", "host", config.Host, "port", config.Port,
+		"Configuring server", "host", conf.Host, "port", conf.Port,
 	)
 
-	s := service.NewService(config)
+	s, err := service.NewService(conf, catalog, verifier)
+
+	if err != nil {
+		log.Logger.Fatal("Failed to create service", "err", err)
+	}
+
+	// Reloads apply the same environment variables and flags as at startup
+	watcher := config.NewWatcher(
+		path,
+		config.DefaultWatchInterval,
+		clock.Real(),
+		func() (config.Config, error) {
+			conf, _, err := config.Load(os.Args[1:], os.Environ(), io.Discard)
+			return conf, err
+		},
+		s.Reload,
+	)
 
-	go handleSignals(s)
+	go watcher.Run()
+	go handleSignals(s, watcher)
 
 	log.Logger.Info("Started server :)")
 	err = s.Run()
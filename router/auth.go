@@ -0,0 +1,104 @@
+package router
+
+import (
+	"context"
+
+	"github.com/maria-mz/bash-battle-proto/proto"
+	"github.com/maria-mz/bash-battle-server/log"
+	"github.com/maria-mz/bash-battle-server/server/network"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Authenticator resolves the token a client sends in the "authorization"
+// metadata to the client.
+type Authenticator interface {
+	Authenticate(token string) (*network.Client, error)
+}
+
+// publicMethods can be called without a token.
+var publicMethods = map[string]bool{
+	"/" + proto.BashBattle_ServiceDesc.ServiceName + "/Connect": true,
+}
+
+type clientKey struct{}
+
+// ClientFromContext returns the client an authenticated call was made by.
+func ClientFromContext(ctx context.Context) (*network.Client, bool) {
+	client, ok := ctx.Value(clientKey{}).(*network.Client)
+	return client, ok
+}
+
+func getToken(ctx context.Context) (string, bool) {
+	var token string
+
+	headers, _ := metadata.FromIncomingContext(ctx)
+	auth := headers["authorization"]
+
+	if len(auth) == 0 {
+		return token, false
+	}
+
+	token = auth[0]
+
+	return token, true
+}
+
+// authenticate resolves the caller of a method, and returns a context
+// holding it. Calls to public methods are let through as is.
+func authenticate(ctx context.Context, auth Authenticator, method string) (context.Context, error) {
+	if publicMethods[method] {
+		return ctx, nil
+	}
+
+	token, ok := getToken(ctx)
+
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+
+	client, err := auth.Authenticate(token)
+
+	if err != nil {
+		log.Logger.Warn("Rejected call", "method", method, "err", err)
+		return nil, err
+	}
+
+	return context.WithValue(ctx, clientKey{}, client), nil
+}
+
+// UnaryAuthInterceptor authenticates every unary call but Connect.
+func UnaryAuthInterceptor(auth Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, auth, info.FullMethod)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor authenticates every streaming call.
+func StreamAuthInterceptor(auth Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(stream.Context(), auth, info.FullMethod)
+
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// authenticatedStream is a stream whose context holds its client.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
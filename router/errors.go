@@ -0,0 +1,189 @@
+package router
+
+import (
+	"context"
+	"errors"
+
+	"github.com/maria-mz/bash-battle-server/auth"
+	"github.com/maria-mz/bash-battle-server/config"
+	"github.com/maria-mz/bash-battle-server/game"
+	"github.com/maria-mz/bash-battle-server/log"
+	"github.com/maria-mz/bash-battle-server/server"
+	"github.com/maria-mz/bash-battle-server/server/game_manager"
+	"github.com/maria-mz/bash-battle-server/server/lobby"
+	"github.com/maria-mz/bash-battle-server/server/network"
+	"github.com/maria-mz/bash-battle-server/username"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// ErrorDomain is the domain of the ErrorInfo details attached to errors.
+// Clients can branch on the ErrorInfo reason.
+const ErrorDomain = "bashbattle"
+
+// Reasons of the ErrorInfo details attached to errors.
+const (
+	ReasonTokenMissing         = "TOKEN_MISSING"
+	ReasonTokenNotRecognized   = "TOKEN_NOT_RECOGNIZED"
+	ReasonTokenInvalid         = "TOKEN_INVALID"
+	ReasonTokenExpired         = "TOKEN_EXPIRED"
+	ReasonTokenRevoked         = "TOKEN_REVOKED"
+	ReasonLogoutInGame         = "LOGOUT_IN_GAME"
+	ReasonUsernameTaken        = "USERNAME_TAKEN"
+	ReasonUsernameTooShort     = "USERNAME_TOO_SHORT"
+	ReasonUsernameTooLong      = "USERNAME_TOO_LONG"
+	ReasonUsernameCharacters   = "USERNAME_INVALID_CHARACTERS"
+	ReasonUsernameReserved     = "USERNAME_RESERVED"
+	ReasonUsernameBlocked      = "USERNAME_BLOCKED"
+	ReasonNotInGame            = "NOT_IN_GAME"
+	ReasonAlreadyInGame        = "ALREADY_IN_GAME"
+	ReasonGameNotFound         = "GAME_NOT_FOUND"
+	ReasonGameStarted          = "GAME_STARTED"
+	ReasonGameOver             = "GAME_OVER"
+	ReasonRematchUnavailable   = "REMATCH_UNAVAILABLE"
+	ReasonNotAPlayer           = "NOT_A_PLAYER"
+	ReasonRoundNotStarted      = "ROUND_NOT_STARTED"
+	ReasonSubmissionLate       = "SUBMISSION_LATE"
+	ReasonDuplicateSubmission  = "DUPLICATE_SUBMISSION"
+	ReasonSubmissionPending    = "SUBMISSION_PENDING"
+	ReasonChallengeUnavailable = "CHALLENGE_UNAVAILABLE"
+	ReasonStreamReplaced       = "STREAM_REPLACED"
+	ReasonSlowConsumer         = "SLOW_CONSUMER"
+	ReasonInvalidGameConfig    = "INVALID_GAME_CONFIG"
+	ReasonNotEnoughChallenges  = "NOT_ENOUGH_CHALLENGES"
+)
+
+// errorMapping is the status code and reason an error is sent with.
+type errorMapping struct {
+	err    error
+	code   codes.Code
+	reason string
+}
+
+// errorMappings are checked in order, with errors.Is.
+var errorMappings = []errorMapping{
+	{ErrTokenNotFound, codes.Unauthenticated, ReasonTokenMissing},
+	{server.ErrTokenNotRecognized, codes.Unauthenticated, ReasonTokenNotRecognized},
+	{auth.ErrInvalidToken, codes.Unauthenticated, ReasonTokenInvalid},
+	{auth.ErrTokenExpired, codes.Unauthenticated, ReasonTokenExpired},
+	{auth.ErrTokenRevoked, codes.Unauthenticated, ReasonTokenRevoked},
+	{server.ErrUsernameTaken, codes.AlreadyExists, ReasonUsernameTaken},
+	{network.ErrUsernameTaken, codes.AlreadyExists, ReasonUsernameTaken},
+	{username.ErrTooShort, codes.InvalidArgument, ReasonUsernameTooShort},
+	{username.ErrTooLong, codes.InvalidArgument, ReasonUsernameTooLong},
+	{username.ErrInvalidCharacters, codes.InvalidArgument, ReasonUsernameCharacters},
+	{username.ErrReserved, codes.InvalidArgument, ReasonUsernameReserved},
+	{username.ErrBlocked, codes.InvalidArgument, ReasonUsernameBlocked},
+	{server.ErrNotInGame, codes.FailedPrecondition, ReasonNotInGame},
+	{server.ErrAlreadyInGame, codes.FailedPrecondition, ReasonAlreadyInGame},
+	{server.ErrLogoutInGame, codes.FailedPrecondition, ReasonLogoutInGame},
+	{lobby.ErrGameNotFound, codes.NotFound, ReasonGameNotFound},
+	{game_manager.ErrJoinOnGameStarted, codes.FailedPrecondition, ReasonGameStarted},
+	{game_manager.ErrStreamOnGameOver, codes.FailedPrecondition, ReasonGameOver},
+	{game_manager.ErrRematchUnavailable, codes.FailedPrecondition, ReasonRematchUnavailable},
+	{game_manager.ErrNotAPlayer, codes.PermissionDenied, ReasonNotAPlayer},
+	{game_manager.ErrRoundNotStarted, codes.FailedPrecondition, ReasonRoundNotStarted},
+	{game_manager.ErrSubmissionLate, codes.FailedPrecondition, ReasonSubmissionLate},
+	{game_manager.ErrDuplicateSubmission, codes.AlreadyExists, ReasonDuplicateSubmission},
+	{game_manager.ErrSubmissionPending, codes.FailedPrecondition, ReasonSubmissionPending},
+	{game_manager.ErrChallengeUnavailable, codes.Internal, ReasonChallengeUnavailable},
+	{network.ErrStreamReplaced, codes.Aborted, ReasonStreamReplaced},
+	{network.ErrSlowConsumer, codes.ResourceExhausted, ReasonSlowConsumer},
+	{config.ErrInvalidConfig, codes.InvalidArgument, ReasonInvalidGameConfig},
+	{game.ErrNotEnoughChallenges, codes.FailedPrecondition, ReasonNotEnoughChallenges},
+}
+
+// ToStatus converts an error returned by the server to a gRPC status error.
+// Known errors get their status code and an ErrorInfo detail (plus a
+// BadRequest detail for invalid configs); other errors are Internal.
+// Status errors are returned as is.
+func ToStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	for _, mapping := range errorMappings {
+		if !errors.Is(err, mapping.err) {
+			continue
+		}
+
+		st := status.New(mapping.code, err.Error())
+
+		details := []protoadapt.MessageV1{
+			&errdetails.ErrorInfo{Reason: mapping.reason, Domain: ErrorDomain},
+		}
+
+		if violations := fieldViolations(err); len(violations) > 0 {
+			details = append(details, &errdetails.BadRequest{FieldViolations: violations})
+		}
+
+		withDetails, detailsErr := st.WithDetails(details...)
+
+		if detailsErr != nil {
+			return st.Err()
+		}
+
+		return withDetails.Err()
+	}
+
+	log.Logger.Error("Unexpected error", "err", err)
+
+	return status.Error(codes.Internal, err.Error())
+}
+
+// fieldViolations lists the config fields an error is about, if any.
+func fieldViolations(err error) []*errdetails.BadRequest_FieldViolation {
+	var violations []*errdetails.BadRequest_FieldViolation
+
+	// errors.As only finds the first one, so walk the tree
+	var walk func(err error)
+	walk = func(err error) {
+		switch err := err.(type) {
+		case *config.FieldError:
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       err.Path,
+				Description: err.Problem,
+			})
+		case interface{ Unwrap() []error }:
+			for _, err := range err.Unwrap() {
+				walk(err)
+			}
+		case interface{ Unwrap() error }:
+			walk(err.Unwrap())
+		}
+	}
+
+	walk(err)
+
+	return violations
+}
+
+// UnaryErrorInterceptor converts the errors of unary calls, see ToStatus.
+func UnaryErrorInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		return resp, ToStatus(err)
+	}
+}
+
+// StreamErrorInterceptor converts the errors of streaming calls, see
+// ToStatus.
+func StreamErrorInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return ToStatus(handler(srv, stream))
+	}
+}
@@ -6,14 +6,16 @@ import (
 
 	"github.com/maria-mz/bash-battle-proto/proto"
 	"github.com/maria-mz/bash-battle-server/server"
-	"google.golang.org/grpc/metadata"
+	"github.com/maria-mz/bash-battle-server/server/network"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 var ErrTokenNotFound = errors.New("token is missing")
 
 // ServerRouter is the API for the BashBattle gRPC service.
-// Handles authorization, directs processing of calls to the internal server.
+// Directs processing of calls to the internal server. Calls are
+// authenticated beforehand, by the auth interceptors, and their errors
+// converted to status errors afterwards, by the error interceptors.
 type ServerRouter struct {
 	proto.UnimplementedBashBattleServer
 	server *server.Server
@@ -23,19 +25,15 @@ func NewServerRouter(s *server.Server) *ServerRouter {
 	return &ServerRouter{server: s}
 }
 
-func (s *ServerRouter) getToken(ctx context.Context) (string, bool) {
-	var token string
+// getClient returns the caller, resolved by the auth interceptors.
+func (s *ServerRouter) getClient(ctx context.Context) (*network.Client, error) {
+	client, ok := ClientFromContext(ctx)
 
-	headers, _ := metadata.FromIncomingContext(ctx)
-	auth := headers["authorization"]
-
-	if len(auth) == 0 {
-		return token, false
+	if !ok {
+		return nil, ErrTokenNotFound
 	}
 
-	token = auth[0]
-
-	return token, true
+	return client, nil
 }
 
 func (s *ServerRouter) Connect(ctx context.Context, in *proto.ConnectRequest) (*proto.ConnectResponse, error) {
@@ -43,50 +41,100 @@ func (s *ServerRouter) Connect(ctx context.Context, in *proto.ConnectRequest) (*
 	return res, err
 }
 
-func (s *ServerRouter) JoinGame(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
-	token, ok := s.getToken(ctx)
+func (s *ServerRouter) CreateGame(ctx context.Context, in *proto.GameConfig) (*proto.GameInfo, error) {
+	client, err := s.getClient(ctx)
 
-	if !ok {
-		return &emptypb.Empty{}, ErrTokenNotFound
+	if err != nil {
+		return &proto.GameInfo{}, err
 	}
 
-	err := s.server.JoinGame(token)
+	info, err := s.server.CreateGame(client, in)
+
+	return info, err
+}
+
+func (s *ServerRouter) ListGames(ctx context.Context, _ *emptypb.Empty) (*proto.GameInfos, error) {
+	client, err := s.getClient(ctx)
+
+	if err != nil {
+		return &proto.GameInfos{}, err
+	}
+
+	games, err := s.server.ListGames(client)
+
+	return games, err
+}
+
+func (s *ServerRouter) JoinGame(ctx context.Context, in *proto.JoinGameRequest) (*emptypb.Empty, error) {
+	client, err := s.getClient(ctx)
+
+	if err != nil {
+		return &emptypb.Empty{}, err
+	}
+
+	err = s.server.JoinGame(client, in.GameId)
 
 	return &emptypb.Empty{}, err
 }
 
 func (s *ServerRouter) GetGameConfig(ctx context.Context, _ *emptypb.Empty) (*proto.GameConfig, error) {
-	token, ok := s.getToken(ctx)
+	client, err := s.getClient(ctx)
 
-	if !ok {
-		return &proto.GameConfig{}, ErrTokenNotFound
+	if err != nil {
+		return &proto.GameConfig{}, err
 	}
 
-	config, err := s.server.GetGameConfig(token)
+	config, err := s.server.GetGameConfig(client)
 
 	return config, err
 }
 
 func (s *ServerRouter) GetPlayers(ctx context.Context, _ *emptypb.Empty) (*proto.Players, error) {
-	token, ok := s.getToken(ctx)
+	client, err := s.getClient(ctx)
 
-	if !ok {
-		return &proto.Players{}, ErrTokenNotFound
+	if err != nil {
+		return &proto.Players{}, err
 	}
 
-	players, err := s.server.GetPlayers(token)
+	players, err := s.server.GetPlayers(client)
 
 	return players, err
 }
 
+func (s *ServerRouter) RefreshToken(ctx context.Context, _ *emptypb.Empty) (*proto.ConnectResponse, error) {
+	client, err := s.getClient(ctx)
+
+	if err != nil {
+		return &proto.ConnectResponse{}, err
+	}
+
+	token, _ := getToken(ctx) // Checked by the auth interceptors
+
+	res, err := s.server.RefreshToken(client, token)
+
+	return res, err
+}
+
+func (s *ServerRouter) Logout(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	client, err := s.getClient(ctx)
+
+	if err != nil {
+		return &emptypb.Empty{}, err
+	}
+
+	err = s.server.Logout(client)
+
+	return &emptypb.Empty{}, err
+}
+
 func (s *ServerRouter) Stream(stream proto.BashBattle_StreamServer) error {
-	token, ok := s.getToken(stream.Context())
+	client, err := s.getClient(stream.Context())
 
-	if !ok {
-		return ErrTokenNotFound
+	if err != nil {
+		return err
 	}
 
-	err := s.server.Stream(token, stream)
+	err = s.server.Stream(client, stream)
 
 	return err
 }
@@ -2,13 +2,28 @@ package router
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/maria-mz/bash-battle-proto/proto"
+	"github.com/maria-mz/bash-battle-server/auth"
 	"github.com/maria-mz/bash-battle-server/config"
+	"github.com/maria-mz/bash-battle-server/game"
+	"github.com/maria-mz/bash-battle-server/game/gametest"
 	"github.com/maria-mz/bash-battle-server/log"
 	"github.com/maria-mz/bash-battle-server/server"
+	"github.com/maria-mz/bash-battle-server/server/game_manager"
+	"github.com/maria-mz/bash-battle-server/server/lobby"
+	"github.com/maria-mz/bash-battle-server/server/network"
+	"github.com/maria-mz/bash-battle-server/username"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
 )
 
 var testConfig = config.Config{
@@ -20,6 +35,14 @@ var testConfig = config.Config{
 	},
 }
 
+var testCatalog = game.NewCatalog(
+	game.Challenge{ID: "challenge-1", Question: "question-1"},
+	game.Challenge{ID: "challenge-2", Question: "question-2"},
+	game.Challenge{ID: "challenge-3", Question: "question-3"},
+	game.Challenge{ID: "challenge-4", Question: "question-4"},
+	game.Challenge{ID: "challenge-5", Question: "question-5"},
+)
+
 func TestMain(m *testing.M) {
 	log.InitLogger()
 	m.Run()
@@ -31,45 +54,184 @@ func getAuthContext(token string) context.Context {
 	return ctx
 }
 
+const (
+	connectMethod   = "/BashBattle/Connect"
+	listGamesMethod = "/BashBattle/ListGames"
+)
+
 type authTest struct {
-	name       string
-	ctx        context.Context
-	token      string
-	shouldFail bool
+	name   string
+	ctx    context.Context
+	method string
+	code   codes.Code // codes.OK if the call goes through
+	client bool       // Whether the handler sees a client
 }
 
-func (test authTest) run(t *testing.T) {
-	server := server.NewServer(testConfig)
-	router := NewServerRouter(server)
-
-	token, ok := router.getToken(test.ctx)
+func (test authTest) run(t *testing.T, server *server.Server) {
+	interceptor := UnaryAuthInterceptor(server)
 
-	if test.shouldFail {
-		assert.False(t, ok)
-	} else {
-		assert.Equal(t, test.token, token)
-		assert.True(t, ok)
+	called := false
+	handler := func(ctx context.Context, req any) (any, error) {
+		called = true
+		_, ok := ClientFromContext(ctx)
+		assert.Equal(t, test.client, ok)
+		return nil, nil
 	}
-}
 
-var authTests = []authTest{
-	{
-		name:       "token in header",
-		ctx:        getAuthContext("test-token"),
-		token:      "test-token",
-		shouldFail: false,
-	},
-	{
-		name:       "token not in header",
-		ctx:        context.Background(),
-		shouldFail: true,
-	},
+	_, err := interceptor(test.ctx, nil, &grpc.UnaryServerInfo{FullMethod: test.method}, handler)
+
+	assert.Equal(t, test.code, status.Code(ToStatus(err)))
+	assert.Equal(t, test.code == codes.OK, called)
 }
 
-func TestAuth(t *testing.T) {
+func TestUnaryAuthInterceptor(t *testing.T) {
+	server, _ := server.NewServer(testConfig, testCatalog, gametest.Verifier(t))
+	defer server.Shutdown()
+
+	resp, _ := server.Connect(&proto.ConnectRequest{Username: "player"})
+
+	authTests := []authTest{
+		{
+			name:   "token recognized",
+			ctx:    getAuthContext(resp.Token),
+			method: listGamesMethod,
+			code:   codes.OK,
+			client: true,
+		},
+		{
+			name:   "token not recognized",
+			ctx:    getAuthContext("test-token"),
+			method: listGamesMethod,
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:   "token not in header",
+			ctx:    context.Background(),
+			method: listGamesMethod,
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:   "connect needs no token",
+			ctx:    context.Background(),
+			method: connectMethod,
+			code:   codes.OK,
+		},
+	}
+
 	for _, st := range authTests {
 		t.Run(st.name, func(t *testing.T) {
-			st.run(t)
+			st.run(t, server)
 		})
 	}
 }
+
+// testStream is a server stream with a context, and nothing else.
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamAuthInterceptor(t *testing.T) {
+	srv, _ := server.NewServer(testConfig, testCatalog, gametest.Verifier(t))
+	defer srv.Shutdown()
+
+	resp, _ := srv.Connect(&proto.ConnectRequest{Username: "player"})
+
+	interceptor := StreamAuthInterceptor(srv)
+	info := &grpc.StreamServerInfo{FullMethod: "/BashBattle/Stream", IsClientStream: true, IsServerStream: true}
+
+	var username string
+	handler := func(srv any, stream grpc.ServerStream) error {
+		client, _ := ClientFromContext(stream.Context())
+		username = client.Username
+		return nil
+	}
+
+	err := interceptor(nil, &testStream{ctx: getAuthContext(resp.Token)}, info, handler)
+	assert.Nil(t, err)
+	assert.Equal(t, "player", username)
+
+	err = interceptor(nil, &testStream{ctx: getAuthContext("test-token")}, info, handler)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+}
+
+func TestRouter_Unauthenticated(t *testing.T) {
+	server, _ := server.NewServer(testConfig, testCatalog, gametest.Verifier(t))
+	defer server.Shutdown()
+
+	router := NewServerRouter(server)
+
+	// Called without going through the interceptors
+	_, err := router.ListGames(getAuthContext("test-token"), nil)
+	assert.ErrorIs(t, err, ErrTokenNotFound)
+}
+
+// errorDetails returns the ErrorInfo reason, and the fields of the BadRequest
+// details of a status error.
+func errorDetails(err error) (reason string, fields []string) {
+	for _, detail := range status.Convert(err).Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			reason = detail.GetReason()
+		case *errdetails.BadRequest:
+			for _, violation := range detail.GetFieldViolations() {
+				fields = append(fields, violation.GetField())
+			}
+		}
+	}
+	return reason, fields
+}
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{server.ErrUsernameTaken, codes.AlreadyExists, ReasonUsernameTaken},
+		{server.ErrTokenNotRecognized, codes.Unauthenticated, ReasonTokenNotRecognized},
+		{auth.ErrTokenExpired, codes.Unauthenticated, ReasonTokenExpired},
+		{fmt.Errorf("%w, must be at most 16 characters", username.ErrTooLong), codes.InvalidArgument, ReasonUsernameTooLong},
+		{lobby.ErrGameNotFound, codes.NotFound, ReasonGameNotFound},
+		{fmt.Errorf("joining: %w", game_manager.ErrJoinOnGameStarted), codes.FailedPrecondition, ReasonGameStarted},
+		{network.ErrStreamReplaced, codes.Aborted, ReasonStreamReplaced},
+		{errors.New("disk on fire"), codes.Internal, ""},
+		{context.Canceled, codes.Canceled, ""},
+	}
+
+	for _, test := range tests {
+		err := ToStatus(test.err)
+		reason, _ := errorDetails(err)
+
+		assert.Equal(t, test.code, status.Code(err), test.err.Error())
+		assert.Equal(t, test.reason, reason, test.err.Error())
+		assert.Equal(t, test.err.Error(), status.Convert(err).Message())
+	}
+
+	assert.Nil(t, ToStatus(nil))
+
+	// Already a status
+	err := status.Error(codes.Unavailable, "try later")
+	assert.Equal(t, err, ToStatus(err))
+}
+
+func TestToStatus_InvalidConfig(t *testing.T) {
+	server, _ := server.NewServer(testConfig, testCatalog, gametest.Verifier(t))
+	defer server.Shutdown()
+
+	resp, _ := server.Connect(&proto.ConnectRequest{Username: "player"})
+	client, _ := server.Authenticate(resp.Token)
+
+	_, err := server.CreateGame(client, &proto.GameConfig{MaxPlayers: protobuf.Int32(1000), Rounds: protobuf.Int32(-1)})
+	err = ToStatus(err)
+
+	reason, fields := errorDetails(err)
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, ReasonInvalidGameConfig, reason)
+	assert.Equal(t, []string{"maxPlayers", "rounds"}, fields)
+}
@@ -2,17 +2,32 @@ package game_manager
 
 import (
 	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	pb "github.com/maria-mz/bash-battle-proto/proto"
+	"github.com/maria-mz/bash-battle-server/clock"
 	"github.com/maria-mz/bash-battle-server/config"
 	"github.com/maria-mz/bash-battle-server/game"
 	"github.com/maria-mz/bash-battle-server/log"
 	"github.com/maria-mz/bash-battle-server/server/network"
+	"github.com/maria-mz/bash-battle-server/utils"
 )
 
+// How long players have to opt into a rematch once the game is over.
+const RematchWindow = 30 * time.Second
+
 var ErrJoinOnGameStarted = errors.New("cannot join game: game already started")
 var ErrStreamOnGameOver = errors.New("cannot stream game: game is over")
+var ErrRematchUnavailable = errors.New("cannot rematch: game is not over")
+var ErrLeaveUnavailable = errors.New("cannot leave: game is not over")
+var ErrNotAPlayer = errors.New("not a player in this game")
+var ErrRoundNotStarted = errors.New("cannot submit: round has not started")
+var ErrSubmissionLate = errors.New("cannot submit: submission window is closed")
+var ErrDuplicateSubmission = errors.New("cannot submit: already submitted for this round")
+var ErrSubmissionPending = errors.New("cannot submit: previous submission is still being verified")
+var ErrChallengeUnavailable = errors.New("cannot load round: no challenge files could be read")
 
 type state int
 
@@ -25,86 +40,232 @@ const (
 	Terminated
 )
 
+var stateNames = map[state]string{
+	Lobby:      "lobby",
+	Load:       "load",
+	Play:       "play",
+	Submission: "submission",
+	Done:       "done",
+	Terminated: "terminated",
+}
+
+func (s state) String() string {
+	return stateNames[s]
+}
+
+// GameManager runs a game. It is safe for concurrent use: everything below
+// mu is only touched with mu held, by the exported methods and the
+// handlers of runner events, client messages and finished broadcasts.
 type GameManager struct {
 	network    *network.Network
 	clientMsgs <-chan network.ClientMsg
 
-	gameData         *game.GameData
-	gameRunner       *game.GameRunner
-	gameRunnerEvents <-chan game.RunnerEvent
+	catalog  *game.Catalog
+	verifier *game.Verifier
+	scoring  game.ScoringStrategy
+	clock    clock.Clock
+
+	mu sync.Mutex
+
+	gameData   *game.GameData
+	gameRunner *game.GameRunner
+
+	// Results of the games played before the last reset, oldest first
+	results []game.GameResult
+
+	// Players who opted into a rematch, while the game is over
+	rematchPlayers utils.Set[string]
+	rematchWindow  time.Duration
+	rematchTimer   clock.Timer
+
+	roundStartsAt  time.Time // When the countdown to the current round ends
+	roundStartedAt time.Time // Zero until the current round starts
+
+	// The round last loaded, replayed to clients that reconnect
+	loadedRound     int
+	loadedChallenge game.Challenge
+	loadedFiles     game.ChallengeFiles
+
+	// Players who made their final submission while the window was open
+	finalSubmissions utils.Set[string]
+
+	// Players whose submission is being verified, signaled on verified
+	// whenever one is done
+	verifying utils.Set[string]
+	verified  *sync.Cond
+
+	// Set once the submission window has closed, while the round waits on
+	// the submissions still being verified to be scored
+	windowClosed bool
 
 	state state
+
+	// Activity, read by the lobby to find finished and abandoned games
+	activeClients int
+	lastActivity  time.Time
+	finishedAt    time.Time
 }
 
-func NewGameManager(config config.GameConfig) *GameManager {
-	broadcaster, clientMsgs := network.NewNetwork()
-	gameData := game.NewGameData(config)
-	gameRunner, gameRunnerEvents := game.NewGameRunner(gameData)
+// NewGameManager creates a game in the lobby, timed by clk. Submissions
+// are checked by verifier, which may be shared with other games.
+func NewGameManager(config config.GameConfig, catalog *game.Catalog, verifier *game.Verifier, clk clock.Clock) (*GameManager, error) {
+	challenges, err := catalog.Select(config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	scoring, err := game.NewScoringStrategy(config.Scoring)
+
+	if err != nil {
+		return nil, err
+	}
+
+	broadcaster, clientMsgs := network.NewNetwork(config.GetAckTimeout(), clk)
+	gameData := game.NewGameData(config, challenges)
+	gameRunner, gameRunnerEvents := game.NewGameRunner(gameData, clk)
 
 	gm := &GameManager{
 		network:          broadcaster,
 		clientMsgs:       clientMsgs,
+		catalog:          catalog,
+		verifier:         verifier,
+		scoring:          scoring,
 		gameData:         gameData,
 		gameRunner:       gameRunner,
-		gameRunnerEvents: gameRunnerEvents,
+		clock:            clk,
+		rematchPlayers:   utils.NewSet[string](),
+		finalSubmissions: utils.NewSet[string](),
+		verifying:        utils.NewSet[string](),
+		rematchWindow:    RematchWindow,
+		lastActivity:     clk.Now(),
 	}
 
-	go gm.handleRunnerEvents()
+	gm.verified = sync.NewCond(&gm.mu)
+
+	go gm.handleRunnerEvents(gameRunnerEvents)
 	go gm.handleClientMsgs()
 
-	return gm
+	return gm, nil
 }
 
-func (gm *GameManager) handleRunnerEvents() {
-	for event := range gm.gameRunnerEvents {
-		round := gm.gameRunner.GetCurrentRound()
+func (gm *GameManager) handleRunnerEvents(events <-chan game.RunnerEvent) {
+	for event := range events {
+		gm.handleRunnerEvent(event)
+	}
 
-		switch event {
-		case game.CountingDown:
-			gm.onCountingDown(round)
+	log.Logger.Info("exiting loop!!!!!!\n")
+}
 
-		case game.RoundStarted:
-			gm.onRoundStarted(round)
+func (gm *GameManager) handleRunnerEvent(event game.RunnerEvent) {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
 
-		case game.RoundEnded:
-			gm.onRoundEnded(round)
-		}
+	if gm.state == Terminated {
+		return
 	}
 
-	log.Logger.Info("exiting loop!!!!!!\n")
+	round := gm.gameRunner.GetCurrentRound()
+
+	switch event {
+	case game.CountingDown:
+		gm.onCountingDown(round)
+
+	case game.RoundStarted:
+		gm.onRoundStarted(round)
+
+	case game.RoundEnded:
+		gm.onRoundEnded(round)
+	}
 }
 
 func (gm *GameManager) onCountingDown(round int) {
-	roundStartsAt := time.Now().Add(gm.gameData.GetCountdownDuration())
-	go gm.network.BroadcastCountdown(round, roundStartsAt)
+	gm.roundStartsAt = gm.clock.Now().Add(gm.gameData.GetCountdownDuration())
+	go gm.network.BroadcastCountdown(round, gm.roundStartsAt)
 }
 
 func (gm *GameManager) onRoundStarted(round int) {
-	roundEndsAt := time.Now().Add(gm.gameData.GetRoundDuration())
+	gm.roundStartedAt = gm.clock.Now()
+	roundEndsAt := gm.roundStartedAt.Add(gm.gameData.GetRoundDuration())
 	go gm.network.BroadcastRoundStart(round, roundEndsAt)
 }
 
 func (gm *GameManager) onRoundEnded(round int) {
 	gm.state = Submission
-	go gm.network.BroadcastSubmitScore(round, gm.onSubmitScoreBroadcasted)
+
+	config := gm.gameData.Config
+	go gm.network.BroadcastSubmitScore(round, config.GetSubmissionDuration(), gm.onSubmitScoreBroadcasted)
 }
 
-func (gm *GameManager) onSubmitScoreBroadcasted() {
+func (gm *GameManager) onSubmitScoreBroadcasted(stragglers []string) {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	if gm.state == Terminated {
+		return
+	}
+
+	// Submissions made in time count, even if they are still being verified
+	gm.windowClosed = true
+
+	for gm.verifying.Size() > 0 {
+		gm.verified.Wait()
+	}
+
+	if gm.state == Terminated {
+		return
+	}
+
+	round := gm.gameRunner.GetCurrentRound()
+
+	if len(stragglers) > 0 {
+		log.Logger.Warn("Scoring round without every submission", "round", round, "stragglers", stragglers)
+	}
+	players := gm.gameData.GetPlayers()
+
+	for _, player := range players {
+		if _, ok := player.Scores[round]; !ok {
+			log.Logger.Info("Player forfeited round", "username", player.Name, "round", round)
+			player.SetRoundScore(game.Score{Round: round, Forfeit: true})
+		}
+	}
+
+	game.ScoreRound(gm.scoring, players, round, gm.gameData.GetRoundDuration())
+	standings := game.BuildLeaderboard(players)
+
 	if gm.gameRunner.IsFinalRound() {
 		gm.state = Done
-		gm.network.BroadcastGameOver()
+		gm.setFinished()
+		gm.results = append(gm.results, game.NewGameResult(gm.gameData, standings, gm.finishedAt))
+		gm.network.BroadcastGameOver(standings)
+		gm.openRematch()
 	} else {
+		gm.network.BroadcastLeaderboard(round, standings)
 		gm.state = Load
 		gm.loadNextRound()
 	}
 }
 
-func (gm *GameManager) onLoadRoundBroadcasted() {
+func (gm *GameManager) onLoadRoundBroadcasted(stragglers []string) {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	if gm.state == Terminated {
+		return
+	}
+
+	if len(stragglers) > 0 {
+		log.Logger.Warn("Starting round without every player loaded", "round", gm.loadedRound, "stragglers", stragglers)
+	}
+
 	gm.state = Play
 	gm.gameRunner.RunRound()
 }
 
 func (gm *GameManager) AddClient(client *network.Client) error {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
 	if gm.state != Lobby {
 		return ErrJoinOnGameStarted
 	}
@@ -117,6 +278,7 @@ func (gm *GameManager) AddClient(client *network.Client) error {
 	player := game.NewPlayer(client.Username)
 
 	gm.gameData.AddPlayer(player)
+	gm.touch()
 	gm.network.BroadcastPlayerJoin(player)
 
 	if gm.gameData.IsGameFull() {
@@ -127,54 +289,714 @@ func (gm *GameManager) AddClient(client *network.Client) error {
 	return nil
 }
 
+// GetPlayers returns copies of the players, safe to read while the game
+// goes on.
 func (gm *GameManager) GetPlayers() []*game.Player {
-	return gm.gameData.GetPlayers()
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	players := gm.gameData.GetPlayers()
+
+	for i, player := range players {
+		players[i] = player.Clone()
+	}
+
+	return players
+}
+
+func (gm *GameManager) HasPlayer(username string) bool {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	return gm.gameData.HasPlayer(username)
+}
+
+// Results returns the results of the games played before the last reset,
+// oldest first.
+func (gm *GameManager) Results() []game.GameResult {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	return append([]game.GameResult(nil), gm.results...)
+}
+
+func (gm *GameManager) GetConfig() config.GameConfig {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	return gm.gameData.Config
+}
+
+func (gm *GameManager) State() state {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	return gm.state
+}
+
+func (gm *GameManager) NumPlayers() int {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	return gm.gameData.NumPlayers()
+}
+
+func (gm *GameManager) IsFull() bool {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	return gm.gameData.IsGameFull()
+}
+
+func (gm *GameManager) IsOver() bool {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	return gm.state == Done || gm.state == Terminated
+}
+
+// Terminate stops the game for good, and ends the clients' streams. The
+// round in progress (if any) ends right away, without being scored.
+func (gm *GameManager) Terminate() {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	gm.terminate(ErrStreamOnGameOver)
+}
+
+// terminate stops the game, ending the clients' streams with err.
+func (gm *GameManager) terminate(err error) {
+	if gm.rematchTimer != nil {
+		gm.rematchTimer.Stop()
+	}
+
+	gm.gameRunner.Stop()
+	gm.state = Terminated
+	gm.setFinished()
+
+	gm.network.Close(err)
+}
+
+// Rematch opts a player into playing again once the game is over. The game
+// is reset as soon as every player has opted in, or when the rematch window
+// closes, keeping only the players who opted in.
+func (gm *GameManager) Rematch(username string) error {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	if gm.state != Done {
+		return ErrRematchUnavailable
+	}
+
+	if !gm.gameData.HasPlayer(username) {
+		return ErrNotAPlayer
+	}
+
+	gm.rematchPlayers.Add(username)
+
+	log.Logger.Info("Player opted into rematch", "username", username)
+
+	if gm.rematchPlayers.Size() == gm.gameData.NumPlayers() {
+		gm.rematchTimer.Stop()
+		return gm.reset()
+	}
+
+	return nil
+}
+
+// Leave removes a player from a game that is over, so they can join
+// another one. The game no longer streams to their client, nor waits on
+// them for a rematch.
+func (gm *GameManager) Leave(username string) error {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	if gm.state != Done && gm.state != Terminated {
+		return ErrLeaveUnavailable
+	}
+
+	player, ok := gm.gameData.GetPlayer(username)
+
+	if !ok {
+		return ErrNotAPlayer
+	}
+
+	gm.gameData.RemovePlayer(username)
+	gm.network.RemoveClient(username)
+	gm.rematchPlayers.Delete(username)
+
+	log.Logger.Info("Player left finished game", "username", username)
+
+	if gm.state == Terminated {
+		return nil // Nobody left to tell
+	}
+
+	gm.network.BroadcastPlayerLeave(&player)
+
+	// Everyone still here may have opted in already
+	if gm.rematchPlayers.Size() > 0 && gm.rematchPlayers.Size() == gm.gameData.NumPlayers() {
+		gm.rematchTimer.Stop()
+		return gm.reset()
+	}
+
+	return nil
+}
+
+func (gm *GameManager) openRematch() {
+	gm.rematchTimer = gm.clock.AfterFunc(gm.rematchWindow, gm.onRematchWindowClosed)
+}
+
+func (gm *GameManager) onRematchWindowClosed() {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	if gm.state != Done {
+		return
+	}
+
+	if gm.rematchPlayers.Size() == 0 {
+		log.Logger.Info("Rematch window closed, nobody opted in")
+		return
+	}
+
+	if err := gm.reset(); err != nil {
+		log.Logger.Error("Failed to reset game for rematch", "err", err)
+	}
 }
 
+// reset starts a new game with the same config and the players who opted
+// into the rematch, back in the lobby. Results of the finished game stay
+// in gm.results.
+func (gm *GameManager) reset() error {
+	config := gm.gameData.Config
+
+	challenges, err := gm.catalog.Select(config)
+
+	if err != nil {
+		return err
+	}
+
+	gameData := game.NewGameData(config, challenges)
+
+	for _, player := range gm.gameData.GetPlayers() {
+		if gm.rematchPlayers.Contains(player.Name) {
+			gameData.AddPlayer(game.NewPlayer(player.Name))
+		} else {
+			gm.network.RemoveClient(player.Name)
+		}
+	}
+
+	gameRunner, gameRunnerEvents := game.NewGameRunner(gameData, gm.clock)
+
+	gm.gameRunner.Stop()
+	gm.gameData = gameData
+	gm.gameRunner = gameRunner
+	gm.roundStartsAt = time.Time{}
+	gm.roundStartedAt = time.Time{}
+	gm.loadedRound = 0
+	gm.loadedChallenge = game.Challenge{}
+	gm.loadedFiles = game.ChallengeFiles{}
+	gm.rematchPlayers.Clear()
+	gm.finalSubmissions.Clear()
+	gm.windowClosed = false
+	gm.rematchTimer = nil
+	gm.state = Lobby
+	gm.clearFinished()
+
+	go gm.handleRunnerEvents(gameRunnerEvents)
+
+	log.Logger.Info("Game reset for rematch", "players", gameData.NumPlayers())
+
+	// Kept players get to see who is in the new lobby
+	for _, player := range gameData.GetPlayers() {
+		gm.network.BroadcastPlayerJoin(player)
+	}
+
+	if gameData.IsGameFull() {
+		gm.state = Load
+		gm.loadNextRound()
+	}
+
+	return nil
+}
+
+// NumActiveClients returns the number of clients currently streaming.
+func (gm *GameManager) NumActiveClients() int {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	return gm.activeClients
+}
+
+// QueueStats returns the outbound queue stats of every streaming client.
+func (gm *GameManager) QueueStats() map[string]network.QueueStats {
+	return gm.network.QueueStats()
+}
+
+// LastActivity returns when a client last joined, or started or stopped
+// streaming.
+func (gm *GameManager) LastActivity() time.Time {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	return gm.lastActivity
+}
+
+// FinishedAt returns when the game ended. Zero if it hasn't.
+func (gm *GameManager) FinishedAt() time.Time {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	return gm.finishedAt
+}
+
+func (gm *GameManager) touch() {
+	gm.lastActivity = gm.clock.Now()
+}
+
+func (gm *GameManager) setFinished() {
+	if gm.finishedAt.IsZero() {
+		gm.finishedAt = gm.clock.Now()
+	}
+}
+
+func (gm *GameManager) clearFinished() {
+	gm.finishedAt = time.Time{}
+	gm.lastActivity = gm.clock.Now()
+}
+
+func (gm *GameManager) setClientActive(active bool) {
+	if active {
+		gm.activeClients++
+	} else {
+		gm.activeClients--
+	}
+
+	gm.lastActivity = gm.clock.Now()
+}
+
+// ListenForClientMsgs streams the game to the client until the stream ends.
+// Players can stream again after dropping, or after the game is over to
+// wait for a rematch.
 func (gm *GameManager) ListenForClientMsgs(client *network.Client) error {
-	if gm.state == Done {
-		return ErrStreamOnGameOver
+	if err := gm.startStreaming(client); err != nil {
+		return err
 	}
+
 	err := gm.network.ListenForClientMsgs(client.Username) // Blocking
+
+	gm.stopStreaming(client.Username, err)
+
 	return err
 }
 
+// startStreaming marks the client as streaming, and starts its stream,
+// catching it up with a snapshot if the game has started. The snapshot is
+// queued under the lock, so no broadcast can get ahead of it.
+func (gm *GameManager) startStreaming(client *network.Client) error {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	if gm.state == Terminated {
+		return ErrStreamOnGameOver
+	}
+
+	gm.setClientActive(true)
+	gm.gameData.SetPlayerAbsent(client.Username, false)
+	gm.resumeRound()
+
+	var snapshot []*pb.Event
+
+	if gm.state != Lobby {
+		snapshot = gm.snapshot()
+	}
+
+	gm.network.StartStream(client, snapshot)
+
+	return nil
+}
+
+func (gm *GameManager) stopStreaming(username string, err error) {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	gm.setClientActive(false)
+
+	if errors.Is(err, network.ErrStreamReplaced) {
+		return // Still connected, on the new stream
+	}
+
+	if gm.state == Terminated {
+		return // Nobody left to tell
+	}
+
+	gm.onClientDisconnected(username)
+}
+
+// snapshot builds the events a client needs to catch up on the game as it
+// is now: the leaderboard so far, then the current round as it was
+// broadcast (loaded, counting down or started, and waiting on submissions),
+// or the results if the game is over.
+func (gm *GameManager) snapshot() []*pb.Event {
+	players := gm.gameData.GetPlayers()
+	standings := game.BuildLeaderboard(players)
+
+	if gm.state == Done {
+		return []*pb.Event{network.BuildGameOverEvent(standings)}
+	}
+
+	var events []*pb.Event
+
+	round := gm.gameRunner.GetCurrentRound()
+	scoredRounds := round
+
+	if gm.state != Load {
+		scoredRounds-- // The current round isn't scored yet
+	}
+
+	if scoredRounds > 0 {
+		events = append(events, network.BuildLeaderboardEvent(scoredRounds, standings))
+	}
+
+	if gm.loadedRound == 0 {
+		return events
+	}
+
+	events = append(events, network.BuildLoadRoundEvent(
+		gm.loadedRound, gm.loadedChallenge, gm.loadedFiles,
+	))
+
+	if gm.state == Load || gm.loadedRound != round {
+		return events // Not started yet
+	}
+
+	if gm.roundStartedAt.IsZero() {
+		events = append(events, network.BuildCountingDownEvent(round, gm.roundStartsAt))
+		return events
+	}
+
+	roundEndsAt := gm.roundStartedAt.Add(gm.gameData.GetRoundDuration())
+	events = append(events, network.BuildRoundStartedEvent(round, roundEndsAt))
+
+	if gm.state == Submission {
+		events = append(events, network.BuildSubmitRoundScoreEvent())
+	}
+
+	return events
+}
+
+// onClientDisconnected handles a client's stream ending. In the lobby the
+// player is removed, freeing their spot. Once the game has started they
+// are only marked absent, so the rounds go on for everyone else.
+func (gm *GameManager) onClientDisconnected(username string) {
+	player, ok := gm.gameData.GetPlayer(username)
+
+	if !ok {
+		return
+	}
+
+	if gm.state == Lobby {
+		gm.gameData.RemovePlayer(username)
+		gm.network.RemoveClient(username)
+		log.Logger.Info("Removed disconnected player", "username", username)
+	} else {
+		gm.gameData.SetPlayerAbsent(username, true)
+		log.Logger.Info("Marked disconnected player absent", "username", username)
+
+		gm.pauseRoundIfAbandoned()
+		gm.endRoundIfFinished()
+	}
+
+	gm.network.BroadcastPlayerLeave(&player)
+}
+
+// handleClientMsgs handles the clients' messages until the game is
+// terminated.
 func (gm *GameManager) handleClientMsgs() {
-	for msg := range gm.clientMsgs {
+	for {
+		var msg network.ClientMsg
+
+		select {
+		case msg = <-gm.clientMsgs:
+		case <-gm.network.Done():
+			return
+		}
+
 		switch ack := msg.Msg.GetAck().(type) {
 
 		case *pb.AckMsg_RoundLoaded:
-			continue // do nothing
+			continue // Tracked by the network, nothing to do
 
 		case *pb.AckMsg_RoundSubmission:
-			if gm.state == Submission {
-				gm.makeSubmission(ack.RoundSubmission.RoundStats, msg.Username)
-			}
+			// Verifying can take a while, don't hold up other players
+			go gm.makeSubmission(ack.RoundSubmission.RoundStats, msg.Username)
 		}
 	}
 }
 
+// makeSubmission verifies a player's command for the current round.
+// Players can keep submitting until they win the round, or until it ends
+// early once everyone has submitted; every wrong submission before that
+// counts as a wrong attempt. Once the round ends,
+// players get one final submission until the submission window closes.
+// The client is told whether its submission was accepted.
 func (gm *GameManager) makeSubmission(stats *pb.RoundStats, username string) {
-	score := game.Score{
-		Round: gm.gameRunner.GetCurrentRound(),
-		Win:   stats.Won,
+	sub, err := gm.acceptSubmission(username)
+	round := sub.round
+
+	if err != nil {
+		log.Logger.Warn("Rejected submission", "username", username, "err", err)
+		gm.network.SendSubmissionResult(username, round, false, false, err.Error())
+		gm.network.AckSubmission(username)
+		return
+	}
+
+	// The client's claimed result is ignored, run the command ourselves.
+	// This can take a while, so it's done without holding the lock, and
+	// only a few at once across all games.
+	won, err := gm.verifier.Verify(sub.challenge, stats.Command)
+
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	// Once recorded or rejected, the round can be scored
+	defer gm.network.AckSubmission(username)
+	defer gm.verified.Broadcast()
+
+	gm.verifying.Delete(username)
+
+	if err != nil && !errors.Is(err, game.ErrVerifyTimeout) {
+		log.Logger.Error(
+			"Failed to verify submission", "username", username, "err", err,
+		)
+		gm.network.SendSubmissionResult(username, round, false, false, err.Error())
+		return
+	}
+
+	// The round may have been scored while the command ran
+	if !gm.acceptsSubmissions() || gm.gameRunner.GetCurrentRound() != round {
+		log.Logger.Warn("Dropped submission, round is over", "username", username)
+		gm.network.SendSubmissionResult(username, round, false, false, ErrSubmissionLate.Error())
+		return
 	}
 
 	player, ok := gm.gameData.GetPlayer(username)
 
 	if !ok {
-		log.Logger.Fatal("failed to set score for player", "username", username)
+		return
 	}
+
+	score := player.Scores[round]
+	score.Round = round
+	score.CmdUsed = stats.Command
+
+	if won {
+		score.Win = true
+		score.SolveTime = sub.solveTime
+	} else {
+		score.WrongAttempts++
+	}
+
+	if gm.state == Submission {
+		gm.finalSubmissions.Add(username)
+	}
+
+	log.Logger.Info("Verified submission", "username", username, "score", score)
+
 	player.SetRoundScore(score)
+	gm.network.SendSubmissionResult(username, round, true, won, "")
+
+	gm.endRoundIfFinished()
+}
+
+// endRoundIfFinished ends the round early once every player still
+// connected has a submission recorded for it, right or wrong.
+func (gm *GameManager) endRoundIfFinished() {
+	if gm.state != Play || gm.roundStartedAt.IsZero() {
+		return
+	}
+
+	round := gm.gameRunner.GetCurrentRound()
+	present := 0
+
+	for _, player := range gm.gameData.GetPlayers() {
+		if player.Absent {
+			continue
+		}
+		if _, submitted := player.Scores[round]; !submitted {
+			return
+		}
+		present++
+	}
+
+	if present == 0 {
+		return
+	}
+
+	if err := gm.gameRunner.EndRound(); err == nil {
+		log.Logger.Info("Every player submitted, ending round early", "round", round)
+	}
+}
+
+// pauseRoundIfAbandoned pauses the round timers once every player is
+// disconnected, so nobody misses any of the round.
+func (gm *GameManager) pauseRoundIfAbandoned() {
+	if gm.state == Done {
+		return
+	}
+
+	for _, player := range gm.gameData.GetPlayers() {
+		if !player.Absent {
+			return
+		}
+	}
+
+	gm.gameRunner.Pause()
+	log.Logger.Info("Every player left, paused round", "round", gm.gameRunner.GetCurrentRound())
+}
+
+// resumeRound resumes the round timers if they were paused, moving the
+// countdown or round times along by as long as they were paused.
+func (gm *GameManager) resumeRound() {
+	if !gm.gameRunner.IsPaused() {
+		return
+	}
+
+	endsAt := gm.gameRunner.Resume()
+	log.Logger.Info("Player is back, resumed round", "round", gm.gameRunner.GetCurrentRound())
+
+	if endsAt.IsZero() {
+		return
+	}
+
+	if gm.roundStartedAt.IsZero() {
+		gm.roundStartsAt = endsAt
+	} else if gm.state == Play {
+		gm.roundStartedAt = endsAt.Add(-gm.gameData.GetRoundDuration())
+	}
+}
+
+// submission is a submission accepted for verification.
+type submission struct {
+	round     int
+	challenge game.Challenge
+	solveTime time.Duration // From round start to when the submission arrived
+}
+
+// acceptSubmission checks if the player can submit for the current round,
+// and returns the submission if so. The player is then marked as verifying,
+// until makeSubmission is done with the submission. The solve time is taken
+// here, so waiting to be verified doesn't count against the player.
+func (gm *GameManager) acceptSubmission(username string) (submission, error) {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	round := gm.gameRunner.GetCurrentRound()
+	rejected := submission{round: round}
+
+	switch {
+	case gm.state == Done || gm.state == Terminated || gm.windowClosed:
+		return rejected, ErrSubmissionLate
+	case !gm.acceptsSubmissions() || gm.roundStartedAt.IsZero():
+		return rejected, ErrRoundNotStarted
+	}
+
+	challenge, ok := gm.gameData.GetChallenge(round - 1) // 0-based
+
+	if !ok {
+		log.Logger.Fatal("No challenge found for round", "round", round)
+	}
+
+	player, ok := gm.gameData.GetPlayer(username)
+
+	if !ok {
+		return rejected, ErrNotAPlayer
+	}
+
+	if player.Scores[round].Win || gm.finalSubmissions.Contains(username) {
+		return rejected, ErrDuplicateSubmission
+	}
+
+	// One at a time, so attempts are counted in order
+	if gm.verifying.Contains(username) {
+		return rejected, ErrSubmissionPending
+	}
+
+	gm.verifying.Add(username)
+
+	accepted := submission{
+		round:     round,
+		challenge: challenge,
+		solveTime: gm.clock.Since(gm.roundStartedAt),
+	}
+
+	return accepted, nil
+}
+
+func (gm *GameManager) acceptsSubmissions() bool {
+	return gm.state == Play || gm.state == Submission
 }
 
 func (gm *GameManager) loadNextRound() {
+	gm.roundStartedAt = time.Time{}
+	gm.finalSubmissions.Clear()
+	gm.windowClosed = false
+
 	round := gm.gameRunner.GetCurrentRound() + 1
+	challenge, files, err := gm.readChallenge(round)
+
+	if err != nil {
+		// Players can't solve a round without its files
+		log.Logger.Error("Ending game, no challenge to load", "round", round, "err", err)
+		gm.terminate(err)
+		return
+	}
+
+	gm.loadedRound = round
+	gm.loadedChallenge = challenge
+	gm.loadedFiles = files
+
+	go gm.network.BroadcastLoadRound(round, challenge, files, gm.onLoadRoundBroadcasted)
+}
+
+// readChallenge reads the files of the round's challenge. If they can't be
+// read, a challenge the game isn't using is read and played in its place.
+// Fails with ErrChallengeUnavailable if no challenge can be read.
+func (gm *GameManager) readChallenge(round int) (game.Challenge, game.ChallengeFiles, error) {
 	challenge, ok := gm.gameData.GetChallenge(round - 1) // 0-based
 
 	if !ok {
 		log.Logger.Fatal("No challenge found for round", "round", round)
 	}
 
-	go gm.network.BroadcastLoadRound(round, challenge, gm.onLoadRoundBroadcasted)
+	maxFileSize := gm.gameData.GetMaxFileSize()
+	files, err := challenge.ReadFiles(maxFileSize)
+
+	if err == nil {
+		return challenge, files, nil
+	}
+
+	log.Logger.Error("Failed to read challenge files", "round", round, "challenge", challenge.ID, "err", err)
+
+	used := make(map[string]bool)
+
+	for _, other := range gm.gameData.Challenges {
+		used[other.ID] = true
+	}
+
+	for _, alternative := range gm.catalog.Alternatives(gm.gameData.Config, used) {
+		files, err := alternative.ReadFiles(maxFileSize)
+
+		if err != nil {
+			log.Logger.Error("Failed to read challenge files", "round", round, "challenge", alternative.ID, "err", err)
+			continue
+		}
+
+		log.Logger.Warn("Replaced challenge", "round", round, "challenge", challenge.ID, "replacement", alternative.ID)
+		gm.gameData.Challenges[round-1] = alternative
+
+		return alternative, files, nil
+	}
+
+	return challenge, game.ChallengeFiles{}, fmt.Errorf("%w: round %d", ErrChallengeUnavailable, round)
 }
@@ -2,10 +2,16 @@ package game_manager
 
 import (
 	"testing"
+	"time"
 
+	pb "github.com/maria-mz/bash-battle-proto/proto"
+	"github.com/maria-mz/bash-battle-server/clock"
 	"github.com/maria-mz/bash-battle-server/config"
+	"github.com/maria-mz/bash-battle-server/game"
+	"github.com/maria-mz/bash-battle-server/game/gametest"
 	"github.com/maria-mz/bash-battle-server/log"
 	"github.com/maria-mz/bash-battle-server/server/network"
+	"github.com/maria-mz/bash-battle-server/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,14 +24,20 @@ var testConfig = config.Config{
 	},
 }
 
+var testCatalog = game.NewCatalog(
+	game.Challenge{ID: "challenge-1", Question: "question-1"},
+	game.Challenge{ID: "challenge-2", Question: "question-2"},
+)
+
 func TestMain(m *testing.M) {
 	log.InitLogger()
 	m.Run()
 }
 
 func TestNewGameManager(t *testing.T) {
-	manager := NewGameManager(testConfig.GameConfig)
+	manager, err := NewGameManager(testConfig.GameConfig, testCatalog, gametest.Verifier(t), clock.Real())
 
+	assert.Nil(t, err)
 	assert.NotNil(t, manager)
 	assert.NotNil(t, manager.network)
 	assert.NotNil(t, manager.gameData)
@@ -34,7 +46,7 @@ func TestNewGameManager(t *testing.T) {
 }
 
 func TestAddClient_Normal(t *testing.T) {
-	manager := NewGameManager(testConfig.GameConfig)
+	manager, _ := NewGameManager(testConfig.GameConfig, testCatalog, gametest.Verifier(t), clock.Real())
 
 	c1 := &network.Client{Username: "player-1"}
 
@@ -46,7 +58,7 @@ func TestAddClient_Normal(t *testing.T) {
 }
 
 func TestAddClient_GameBecomesFull(t *testing.T) {
-	manager := NewGameManager(testConfig.GameConfig)
+	manager, _ := NewGameManager(testConfig.GameConfig, testCatalog, gametest.Verifier(t), clock.Real())
 
 	c1 := &network.Client{Username: "player-1"}
 	c2 := &network.Client{Username: "player-2"}
@@ -56,11 +68,12 @@ func TestAddClient_GameBecomesFull(t *testing.T) {
 	manager.AddClient(c2)
 	manager.AddClient(c3)
 
-	assert.Equal(t, Load, manager.state)
+	// Nobody is streaming to ack the round, so it may already be playing
+	assert.NotEqual(t, Lobby, manager.State())
 }
 
 func TestAddClient_ErrJoinOnGameStarted(t *testing.T) {
-	manager := NewGameManager(testConfig.GameConfig)
+	manager, _ := NewGameManager(testConfig.GameConfig, testCatalog, gametest.Verifier(t), clock.Real())
 
 	c1 := &network.Client{Username: "player-1"}
 	c2 := &network.Client{Username: "player-2"}
@@ -74,3 +87,579 @@ func TestAddClient_ErrJoinOnGameStarted(t *testing.T) {
 
 	assert.Equal(t, err, ErrJoinOnGameStarted)
 }
+
+func TestNewGameManager_ErrNotEnoughChallenges(t *testing.T) {
+	catalog := game.NewCatalog(game.Challenge{ID: "challenge-1"})
+
+	manager, err := NewGameManager(testConfig.GameConfig, catalog, gametest.Verifier(t), clock.Real())
+
+	assert.Nil(t, manager)
+	assert.ErrorIs(t, err, game.ErrNotEnoughChallenges)
+}
+
+func TestMakeSubmission_VerifiesCommand(t *testing.T) {
+	manager, _ := NewGameManager(testConfig.GameConfig, gametest.SortCatalog(t), gametest.Verifier(t), clock.Real())
+	manager.AddClient(&network.Client{Username: "player-1"})
+	manager.gameRunner.RunRound()
+
+	manager.mu.Lock()
+	manager.state = Play
+	manager.roundStartedAt = time.Now()
+	manager.mu.Unlock()
+
+	// Claims a win, but the command doesn't produce the expected output
+	manager.makeSubmission(&pb.RoundStats{Won: true, Command: "cat input.txt"}, "player-1")
+
+	player, _ := manager.gameData.GetPlayer("player-1")
+	assert.False(t, player.Scores[1].Win)
+	assert.Equal(t, "cat input.txt", player.Scores[1].CmdUsed)
+
+	// Claims a loss, but the command is correct
+	manager.makeSubmission(&pb.RoundStats{Won: false, Command: "sort input.txt"}, "player-1")
+
+	player, _ = manager.gameData.GetPlayer("player-1")
+	assert.True(t, player.Scores[1].Win)
+	assert.Equal(t, 1, player.Scores[1].WrongAttempts)
+	assert.Equal(t, "sort input.txt", player.Scores[1].CmdUsed)
+
+	// Round already won, further submissions are ignored
+	manager.makeSubmission(&pb.RoundStats{Command: "cat input.txt"}, "player-1")
+
+	player, _ = manager.gameData.GetPlayer("player-1")
+	assert.True(t, player.Scores[1].Win)
+	assert.Equal(t, "sort input.txt", player.Scores[1].CmdUsed)
+}
+
+func TestMakeSubmission_IgnoredBeforeRoundStarts(t *testing.T) {
+	manager, _ := NewGameManager(testConfig.GameConfig, testCatalog, gametest.Verifier(t), clock.Real())
+	manager.AddClient(&network.Client{Username: "player-1"})
+	manager.gameRunner.RunRound()
+
+	manager.mu.Lock()
+	manager.state = Play
+	manager.mu.Unlock()
+
+	manager.makeSubmission(&pb.RoundStats{Command: "true"}, "player-1")
+
+	player, _ := manager.gameData.GetPlayer("player-1")
+	assert.Empty(t, player.Scores)
+}
+
+func TestMakeSubmission_RejectsLateAndDuplicate(t *testing.T) {
+	manager, _ := NewGameManager(testConfig.GameConfig, gametest.SortCatalog(t), gametest.Verifier(t), clock.Real())
+	manager.AddClient(&network.Client{Username: "player-1"})
+
+	_, err := manager.acceptSubmission("player-1")
+	assert.ErrorIs(t, err, ErrRoundNotStarted)
+
+	manager.gameRunner.RunRound()
+
+	manager.mu.Lock()
+	manager.state = Submission
+	manager.roundStartedAt = time.Now()
+	manager.mu.Unlock()
+
+	_, err = manager.acceptSubmission("player-2")
+	assert.ErrorIs(t, err, ErrNotAPlayer)
+
+	// Still verifying the last one
+	manager.verifying.Add("player-1")
+	_, err = manager.acceptSubmission("player-1")
+	assert.ErrorIs(t, err, ErrSubmissionPending)
+	manager.verifying.Delete("player-1")
+
+	// One final submission once the round has ended
+	manager.makeSubmission(&pb.RoundStats{Command: "true"}, "player-1")
+
+	player, _ := manager.gameData.GetPlayer("player-1")
+	assert.Equal(t, 1, player.Scores[1].WrongAttempts)
+
+	_, err = manager.acceptSubmission("player-1")
+	assert.ErrorIs(t, err, ErrDuplicateSubmission)
+
+	manager.mu.Lock()
+	manager.state = Done
+	manager.mu.Unlock()
+
+	_, err = manager.acceptSubmission("player-1")
+	assert.ErrorIs(t, err, ErrSubmissionLate)
+}
+
+func TestMakeSubmission_VerifiedConcurrently(t *testing.T) {
+	config := testConfig.GameConfig
+	config.MaxPlayers = 4 // Not full, so the game doesn't start on its own
+
+	// Time stands still for the game, only the commands take a while
+	manager, _ := NewGameManager(config, gametest.SortCatalog(t), gametest.Verifier(t), clock.NewFake(time.Now()))
+
+	var streams []*utils.MockStreamServer
+
+	for _, username := range []string{"player-1", "player-2", "player-3"} {
+		mss := utils.NewMockStreamServer()
+		client := &network.Client{Username: username}
+		client.SetStream(network.NewStream(mss, network.DefaultQueueConfig()))
+		manager.AddClient(client)
+
+		go manager.ListenForClientMsgs(client)
+		streams = append(streams, mss)
+	}
+
+	manager.gameRunner.RunRound()
+
+	manager.mu.Lock()
+	manager.state = Play
+	manager.roundStartedAt = time.Now()
+	manager.mu.Unlock()
+
+	// One after the other, the last one would be verified after 3 seconds
+	for _, mss := range streams {
+		mss.AckMsgs <- &pb.AckMsg{Ack: &pb.AckMsg_RoundSubmission{
+			RoundSubmission: &pb.RoundSubmission{RoundStats: &pb.RoundStats{Command: "sleep 1; sort input.txt"}},
+		}}
+	}
+
+	assert.Eventually(t, func() bool {
+		for _, player := range manager.GetPlayers() {
+			if !player.Scores[1].Win {
+				return false
+			}
+		}
+		return true
+	}, 2500*time.Millisecond, 10*time.Millisecond)
+
+	manager.Terminate()
+}
+
+func TestMakeSubmission_SolveTimeTakenOnArrival(t *testing.T) {
+	config := testConfig.GameConfig
+	config.CountdownDuration = 60 // Keeps the runner waiting while time is advanced
+
+	clk := clock.NewFake(time.Now())
+	manager, _ := NewGameManager(config, gametest.SortCatalog(t), gametest.Verifier(t), clk)
+	manager.AddClient(&network.Client{Username: "player-1"})
+	manager.gameRunner.RunRound()
+
+	manager.mu.Lock()
+	manager.state = Play
+	manager.roundStartedAt = clk.Now()
+	manager.mu.Unlock()
+
+	clk.Advance(2 * time.Second)
+
+	done := make(chan struct{})
+
+	// The command takes a while, time passes while it is verified
+	go func() {
+		manager.makeSubmission(&pb.RoundStats{Command: "sleep 1; sort input.txt"}, "player-1")
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		manager.mu.Lock()
+		defer manager.mu.Unlock()
+		return manager.verifying.Contains("player-1")
+	}, time.Second, 10*time.Millisecond)
+
+	clk.Advance(3 * time.Second)
+
+	<-done
+
+	player, _ := manager.gameData.GetPlayer("player-1")
+	assert.True(t, player.Scores[1].Win)
+	assert.Equal(t, 2*time.Second, player.Scores[1].SolveTime)
+
+	manager.Terminate()
+}
+
+func TestScoring_ForfeitsMissingSubmissions(t *testing.T) {
+	manager, _ := NewGameManager(testConfig.GameConfig, gametest.SortCatalog(t), gametest.Verifier(t), clock.Real())
+	manager.AddClient(&network.Client{Username: "player-1"})
+	manager.AddClient(&network.Client{Username: "player-2"})
+	manager.gameRunner.RunRound()
+
+	manager.mu.Lock()
+	manager.state = Submission
+	manager.roundStartedAt = time.Now()
+	manager.mu.Unlock()
+
+	manager.makeSubmission(&pb.RoundStats{Command: "true"}, "player-1")
+	manager.onSubmitScoreBroadcasted([]string{"player-2"})
+
+	player, _ := manager.gameData.GetPlayer("player-1")
+	assert.False(t, player.Scores[1].Forfeit)
+
+	player, _ = manager.gameData.GetPlayer("player-2")
+	assert.True(t, player.Scores[1].Forfeit)
+	assert.Equal(t, 0, player.Scores[1].Points)
+
+	manager.Terminate()
+}
+
+func roundStarted(manager *GameManager) bool {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	return manager.state == Play && !manager.roundStartedAt.IsZero()
+}
+
+func TestMakeSubmission_EndsRoundOnceEveryoneSubmitted(t *testing.T) {
+	clk := clock.NewFake(time.Now())
+
+	manager, _ := NewGameManager(testConfig.GameConfig, gametest.SortCatalog(t), gametest.Verifier(t), clk)
+	manager.AddClient(&network.Client{Username: "player-1"})
+	manager.AddClient(&network.Client{Username: "player-2"})
+
+	manager.mu.Lock()
+	manager.gameData.SetPlayerAbsent("player-2", true)
+	manager.mu.Unlock()
+
+	manager.onLoadRoundBroadcasted(nil)
+
+	clk.BlockUntil(1)
+	clk.Advance(time.Duration(testConfig.GameConfig.CountdownDuration) * time.Second)
+
+	assert.Eventually(t, func() bool {
+		return roundStarted(manager)
+	}, time.Second, time.Millisecond)
+
+	// player-2 is gone, so the round is over once player-1 submits, even
+	// a wrong answer
+	manager.makeSubmission(&pb.RoundStats{Command: "cat input.txt"}, "player-1")
+
+	// Nobody is streaming, so the round is scored right away and the next
+	// one loaded, without the round's time passing
+	assert.Eventually(t, func() bool {
+		manager.mu.Lock()
+		defer manager.mu.Unlock()
+		return manager.loadedRound == 2
+	}, time.Second, time.Millisecond)
+
+	manager.Terminate()
+}
+
+func TestFullGame(t *testing.T) {
+	clk := clock.NewFake(time.Now())
+
+	config := testConfig.GameConfig
+	config.MaxPlayers = 2
+
+	manager, _ := NewGameManager(config, gametest.SortCatalog(t), gametest.Verifier(t), clk)
+
+	// The game starts as soon as it's full
+	manager.AddClient(&network.Client{Username: "player-1"})
+	manager.AddClient(&network.Client{Username: "player-2"})
+
+	for round := 1; round <= config.Rounds; round++ {
+		clk.BlockUntil(1)
+		clk.Advance(time.Duration(config.CountdownDuration) * time.Second)
+
+		assert.Eventually(t, func() bool {
+			return roundStarted(manager)
+		}, time.Second, time.Millisecond)
+
+		if round == 2 {
+			manager.makeSubmission(&pb.RoundStats{Command: "sort input.txt"}, "player-1")
+		}
+
+		clk.BlockUntil(1)
+		clk.Advance(time.Duration(config.RoundDuration) * time.Second)
+	}
+
+	assert.Eventually(t, func() bool {
+		return manager.State() == Done
+	}, time.Second, time.Millisecond)
+
+	results := manager.Results()
+
+	assert.Len(t, results, 1)
+	assert.Equal(t, "player-1", results[0].Standings[0].Player)
+	assert.Equal(t, 1, results[0].Standings[0].RoundsWon)
+
+	// Once the rematch window closes, the game goes back to the lobby with
+	// the players who opted in
+	assert.Nil(t, manager.Rematch("player-1"))
+
+	clk.BlockUntil(1)
+	clk.Advance(RematchWindow)
+
+	assert.Eventually(t, func() bool {
+		return manager.State() == Lobby && manager.NumPlayers() == 1
+	}, time.Second, time.Millisecond)
+}
+
+func TestDisconnect_PausesRoundWhenEveryoneLeft(t *testing.T) {
+	manager, _ := NewGameManager(testConfig.GameConfig, testCatalog, gametest.Verifier(t), clock.Real())
+	client := &network.Client{Username: "player-1"}
+	manager.AddClient(client)
+
+	manager.mu.Lock()
+	manager.state = Play
+	manager.onClientDisconnected("player-1")
+	manager.mu.Unlock()
+
+	assert.True(t, manager.gameRunner.IsPaused())
+
+	manager.startStreaming(client)
+
+	assert.False(t, manager.gameRunner.IsPaused())
+}
+
+func TestNewGameManager_ErrUnknownScoring(t *testing.T) {
+	config := testConfig.GameConfig
+	config.Scoring = "golf"
+
+	manager, err := NewGameManager(config, testCatalog, gametest.Verifier(t), clock.Real())
+
+	assert.Nil(t, manager)
+	assert.NotNil(t, err)
+}
+
+func TestRematch(t *testing.T) {
+	manager, _ := NewGameManager(testConfig.GameConfig, testCatalog, gametest.Verifier(t), clock.Real())
+
+	manager.AddClient(&network.Client{Username: "player-1"})
+	manager.AddClient(&network.Client{Username: "player-2"})
+
+	assert.ErrorIs(t, manager.Rematch("player-1"), ErrRematchUnavailable)
+
+	oldData := manager.gameData
+	manager.state = Done
+	manager.openRematch()
+
+	assert.ErrorIs(t, manager.Rematch("player-3"), ErrNotAPlayer)
+	assert.Nil(t, manager.Rematch("player-1"))
+	assert.Equal(t, Done, manager.state)
+
+	manager.onRematchWindowClosed()
+
+	assert.Equal(t, Lobby, manager.state)
+	assert.NotSame(t, oldData, manager.gameData)
+	assert.True(t, manager.HasPlayer("player-1"))
+	assert.False(t, manager.HasPlayer("player-2"))
+	assert.True(t, manager.FinishedAt().IsZero())
+
+	// Back in the lobby, new players can join
+	assert.Nil(t, manager.AddClient(&network.Client{Username: "player-2"}))
+}
+
+func TestRematch_EveryoneOptsIn(t *testing.T) {
+	manager, _ := NewGameManager(testConfig.GameConfig, testCatalog, gametest.Verifier(t), clock.Real())
+
+	manager.AddClient(&network.Client{Username: "player-1"})
+	manager.AddClient(&network.Client{Username: "player-2"})
+
+	manager.state = Done
+	manager.rematchWindow = time.Hour
+	manager.openRematch()
+
+	manager.Rematch("player-1")
+	manager.Rematch("player-2")
+
+	assert.Equal(t, Lobby, manager.state)
+	assert.Equal(t, 2, manager.NumPlayers())
+}
+
+// streamUntilClosed streams for the client until its mock stream is closed.
+func streamUntilClosed(manager *GameManager, client *network.Client, mss *utils.MockStreamServer) {
+	done := make(chan struct{})
+
+	go func() {
+		manager.ListenForClientMsgs(client)
+		close(done)
+	}()
+
+	mss.Close()
+	<-done
+}
+
+func TestDisconnect_InLobby(t *testing.T) {
+	manager, _ := NewGameManager(testConfig.GameConfig, testCatalog, gametest.Verifier(t), clock.Real())
+
+	mss := utils.NewMockStreamServer()
+	c1 := &network.Client{Username: "player-1"}
+	c1.SetStream(network.NewStream(mss, network.DefaultQueueConfig()))
+
+	manager.AddClient(c1)
+	manager.AddClient(&network.Client{Username: "player-2"})
+
+	streamUntilClosed(manager, c1, mss)
+
+	assert.Equal(t, Lobby, manager.state)
+	assert.False(t, manager.HasPlayer("player-1"))
+	assert.True(t, manager.HasPlayer("player-2"))
+
+	// The name is free again
+	assert.Nil(t, manager.AddClient(&network.Client{Username: "player-1"}))
+}
+
+func TestDisconnect_DuringPlay(t *testing.T) {
+	manager, _ := NewGameManager(testConfig.GameConfig, testCatalog, gametest.Verifier(t), clock.Real())
+
+	mss := utils.NewMockStreamServer()
+	c1 := &network.Client{Username: "player-1"}
+	c1.SetStream(network.NewStream(mss, network.DefaultQueueConfig()))
+
+	manager.AddClient(c1)
+	manager.state = Play
+
+	streamUntilClosed(manager, c1, mss)
+
+	player, ok := manager.gameData.GetPlayer("player-1")
+
+	assert.True(t, ok)
+	assert.True(t, player.Absent)
+}
+
+func TestReconnect_ReplacesStream(t *testing.T) {
+	manager, _ := NewGameManager(testConfig.GameConfig, testCatalog, gametest.Verifier(t), clock.Real())
+
+	mss := utils.NewMockStreamServer()
+	c1 := &network.Client{Username: "player-1"}
+	c1.SetStream(network.NewStream(mss, network.DefaultQueueConfig()))
+	manager.AddClient(c1)
+
+	done := make(chan error)
+	oldStream := c1.Stream()
+
+	go func() {
+		done <- manager.ListenForClientMsgs(c1)
+	}()
+
+	// Once the ack is read, the old stream is being listened to
+	mss.AckMsgs <- &pb.AckMsg{Ack: &pb.AckMsg_RoundLoaded{RoundLoaded: &pb.RoundLoaded{}}}
+	assert.Eventually(t, func() bool { return len(mss.AckMsgs) == 0 }, time.Second, time.Millisecond)
+
+	c1.SetStream(network.NewStream(utils.NewMockStreamServer(), network.DefaultQueueConfig()))
+	oldStream.Close(network.ErrStreamReplaced)
+
+	assert.ErrorIs(t, <-done, network.ErrStreamReplaced)
+	assert.True(t, manager.HasPlayer("player-1")) // Not a disconnect
+}
+
+func TestTerminate_ClosesStreams(t *testing.T) {
+	manager, _ := NewGameManager(testConfig.GameConfig, testCatalog, gametest.Verifier(t), clock.Real())
+
+	mss := utils.NewMockStreamServer()
+	c1 := &network.Client{Username: "player-1"}
+	c1.SetStream(network.NewStream(mss, network.DefaultQueueConfig()))
+	manager.AddClient(c1)
+
+	done := make(chan error)
+
+	go func() {
+		done <- manager.ListenForClientMsgs(c1)
+	}()
+
+	// Once the ack is read, the stream is being listened to
+	mss.AckMsgs <- &pb.AckMsg{Ack: &pb.AckMsg_RoundLoaded{RoundLoaded: &pb.RoundLoaded{}}}
+	assert.Eventually(t, func() bool { return len(mss.AckMsgs) == 0 }, time.Second, time.Millisecond)
+
+	manager.Terminate()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, ErrStreamOnGameOver)
+	case <-time.After(time.Second):
+		t.Fatal("stream still open after the game was terminated")
+	}
+
+	assert.Equal(t, 0, manager.NumActiveClients())
+}
+
+func TestTerminate_StopsHandlingClientMsgs(t *testing.T) {
+	manager, _ := NewGameManager(testConfig.GameConfig, testCatalog, gametest.Verifier(t), clock.Real())
+
+	manager.Terminate()
+
+	done := make(chan struct{})
+
+	go func() {
+		manager.handleClientMsgs() // Same loop as the one started with the game
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("still handling client messages after the game was terminated")
+	}
+}
+
+func TestReconnect_ReplaysSnapshot(t *testing.T) {
+	manager, _ := NewGameManager(testConfig.GameConfig, testCatalog, gametest.Verifier(t), clock.Real())
+
+	manager.AddClient(&network.Client{Username: "player-1"})
+
+	challenge, _ := manager.gameData.GetChallenge(0)
+
+	manager.state = Load
+	manager.loadedRound = 1
+	manager.loadedChallenge = challenge
+
+	events := manager.snapshot()
+
+	assert.Len(t, events, 1)
+	assert.Equal(t, challenge.Question, events[0].GetLoadRound().GetQuestion())
+
+	manager.state = Done
+
+	events = manager.snapshot()
+
+	assert.Len(t, events, 1)
+	assert.NotNil(t, events[0].GetGameOver())
+}
+
+func TestLoadNextRound_ReplacesUnreadableChallenge(t *testing.T) {
+	broken := game.Challenge{ID: "broken", InputFile: "does-not-exist.txt", OutputFile: "does-not-exist.txt"}
+	sort := gametest.FileChallenge(t, "sort", gametest.SortInput, gametest.SortOutput)
+
+	manager, _ := NewGameManager(testConfig.GameConfig, game.NewCatalog(broken, sort), gametest.Verifier(t), clock.Real())
+
+	manager.mu.Lock()
+	manager.gameData.Challenges = map[int]game.Challenge{0: broken}
+	manager.loadNextRound()
+	manager.mu.Unlock()
+
+	assert.Equal(t, "sort", manager.loadedChallenge.ID)
+	assert.Equal(t, []byte(gametest.SortInput), manager.loadedFiles.Input)
+
+	// Played, and verified, in place of the broken one
+	challenge, _ := manager.gameData.GetChallenge(0)
+	assert.Equal(t, "sort", challenge.ID)
+
+	manager.Terminate()
+}
+
+func TestLoadNextRound_EndsGameWithoutChallenge(t *testing.T) {
+	broken := game.Challenge{ID: "broken", InputFile: "does-not-exist.txt", OutputFile: "does-not-exist.txt"}
+	manager, _ := NewGameManager(testConfig.GameConfig, game.NewCatalog(broken, broken), gametest.Verifier(t), clock.Real())
+
+	mss := utils.NewMockStreamServer()
+	c1 := &network.Client{Username: "player-1"}
+	c1.SetStream(network.NewStream(mss, network.DefaultQueueConfig()))
+	manager.AddClient(c1)
+
+	done := make(chan error)
+
+	go func() {
+		done <- manager.ListenForClientMsgs(c1)
+	}()
+
+	// Once the ack is read, the stream is being listened to
+	mss.AckMsgs <- &pb.AckMsg{Ack: &pb.AckMsg_RoundLoaded{RoundLoaded: &pb.RoundLoaded{}}}
+	assert.Eventually(t, func() bool { return len(mss.AckMsgs) == 0 }, time.Second, time.Millisecond)
+
+	manager.mu.Lock()
+	manager.loadNextRound()
+	manager.mu.Unlock()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, ErrChallengeUnavailable)
+	case <-time.After(time.Second):
+		t.Fatal("stream still open after the game ended")
+	}
+
+	assert.True(t, manager.IsOver())
+	assert.Zero(t, manager.loadedRound) // Nothing was sent
+}
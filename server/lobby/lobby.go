@@ -0,0 +1,263 @@
+package lobby
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/maria-mz/bash-battle-proto/proto"
+	"github.com/maria-mz/bash-battle-server/clock"
+	"github.com/maria-mz/bash-battle-server/config"
+	"github.com/maria-mz/bash-battle-server/game"
+	"github.com/maria-mz/bash-battle-server/log"
+	"github.com/maria-mz/bash-battle-server/server/game_manager"
+	"github.com/maria-mz/bash-battle-server/server/network"
+	"github.com/maria-mz/bash-battle-server/utils"
+)
+
+const (
+	// How often finished and abandoned games are cleaned up.
+	CleanupInterval = 1 * time.Minute
+
+	// A game with no connected players is abandoned after this long.
+	AbandonedAfter = 5 * time.Minute
+
+	// A finished game is kept around this long, so players can see results.
+	FinishedGameTTL = 5 * time.Minute
+)
+
+var ErrGameNotFound = errors.New("game not found")
+
+// Game is one game hosted by the server.
+type Game struct {
+	ID        string
+	Manager   *game_manager.GameManager
+	CreatedAt time.Time
+}
+
+func (g *Game) ToProto() *proto.GameInfo {
+	gameConfig := g.Manager.GetConfig()
+
+	return &proto.GameInfo{
+		GameId:     g.ID,
+		Config:     gameConfig.ToProto(),
+		NumPlayers: int32(g.Manager.NumPlayers()),
+		State:      g.Manager.State().String(),
+	}
+}
+
+// Lobby keeps track of all the games on the server: creating them, finding
+// them for players to join, and cleaning them up once they are finished or
+// abandoned.
+type Lobby struct {
+	catalog       *game.Catalog
+	verifier      *game.Verifier // Shared by every game
+	defaultConfig config.GameConfig
+	games         map[string]*Game
+	clock         clock.Clock // Times the lobby and its games
+
+	abandonedAfter  time.Duration
+	finishedGameTTL time.Duration
+
+	mu sync.Mutex
+}
+
+// NewLobby creates an empty lobby, whose games are timed by clk and share
+// verifier.
+func NewLobby(catalog *game.Catalog, verifier *game.Verifier, defaultConfig config.GameConfig, clk clock.Clock) *Lobby {
+	return &Lobby{
+		catalog:         catalog,
+		verifier:        verifier,
+		defaultConfig:   defaultConfig,
+		games:           make(map[string]*Game),
+		clock:           clk,
+		abandonedAfter:  AbandonedAfter,
+		finishedGameTTL: FinishedGameTTL,
+	}
+}
+
+// DefaultConfig returns the config used for games created without one.
+func (lobby *Lobby) DefaultConfig() config.GameConfig {
+	lobby.mu.Lock()
+	defer lobby.mu.Unlock()
+
+	return lobby.defaultConfig
+}
+
+// SetDefaultConfig changes the config of games created from now on. Games
+// already created keep theirs.
+func (lobby *Lobby) SetDefaultConfig(config config.GameConfig) {
+	lobby.mu.Lock()
+	defer lobby.mu.Unlock()
+
+	lobby.defaultConfig = config
+}
+
+// CreateGame creates a new game, waiting in the lobby for players.
+func (lobby *Lobby) CreateGame(config config.GameConfig) (*Game, error) {
+	lobby.mu.Lock()
+	defer lobby.mu.Unlock()
+
+	return lobby.createGame(config)
+}
+
+func (lobby *Lobby) createGame(config config.GameConfig) (*Game, error) {
+	manager, err := game_manager.NewGameManager(config, lobby.catalog, lobby.verifier, lobby.clock)
+
+	if err != nil {
+		return nil, err
+	}
+
+	id := utils.GenerateGameID()
+
+	for lobby.games[id] != nil {
+		id = utils.GenerateGameID()
+	}
+
+	g := &Game{
+		ID:        id,
+		Manager:   manager,
+		CreatedAt: lobby.clock.Now(),
+	}
+
+	lobby.games[id] = g
+
+	log.Logger.Info("Created game", "id", id, "config", config)
+
+	return g, nil
+}
+
+// GetGame returns the game matching the id, if it exists.
+func (lobby *Lobby) GetGame(id string) (*Game, bool) {
+	lobby.mu.Lock()
+	defer lobby.mu.Unlock()
+
+	g, ok := lobby.games[id]
+	return g, ok
+}
+
+// ListGames returns all the games, oldest first.
+func (lobby *Lobby) ListGames() []*Game {
+	lobby.mu.Lock()
+	defer lobby.mu.Unlock()
+
+	return lobby.sortedGames()
+}
+
+func (lobby *Lobby) sortedGames() []*Game {
+	games := make([]*Game, 0, len(lobby.games))
+
+	for _, g := range lobby.games {
+		games = append(games, g)
+	}
+
+	sort.Slice(games, func(i, j int) bool {
+		return games[i].CreatedAt.Before(games[j].CreatedAt)
+	})
+
+	return games
+}
+
+// JoinGame adds the client to the game matching the id.
+func (lobby *Lobby) JoinGame(id string, client *network.Client) (*Game, error) {
+	lobby.mu.Lock()
+	defer lobby.mu.Unlock()
+
+	g, ok := lobby.games[id]
+
+	if !ok {
+		return nil, ErrGameNotFound
+	}
+
+	if err := g.Manager.AddClient(client); err != nil {
+		return nil, err
+	}
+
+	client.SetGameID(g.ID)
+
+	return g, nil
+}
+
+// JoinOpenGame adds the client to the oldest game still waiting for
+// players. If there is none, a game with the default config is created.
+func (lobby *Lobby) JoinOpenGame(client *network.Client) (*Game, error) {
+	lobby.mu.Lock()
+	defer lobby.mu.Unlock()
+
+	for _, g := range lobby.sortedGames() {
+		if g.Manager.State() != game_manager.Lobby || g.Manager.IsFull() {
+			continue
+		}
+
+		if err := g.Manager.AddClient(client); err != nil {
+			continue
+		}
+
+		client.SetGameID(g.ID)
+		return g, nil
+	}
+
+	g, err := lobby.createGame(lobby.defaultConfig)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.Manager.AddClient(client); err != nil {
+		return nil, err
+	}
+
+	client.SetGameID(g.ID)
+
+	return g, nil
+}
+
+// Cleanup removes games that finished more than finishedGameTTL ago, and
+// games nobody has been connected to for abandonedAfter. Returns the IDs
+// of the removed games.
+func (lobby *Lobby) Cleanup(now time.Time) []string {
+	lobby.mu.Lock()
+	defer lobby.mu.Unlock()
+
+	var removed []string
+
+	for id, g := range lobby.games {
+		manager := g.Manager
+
+		finished := manager.IsOver() &&
+			now.Sub(manager.FinishedAt()) > lobby.finishedGameTTL
+
+		abandoned := manager.NumActiveClients() == 0 &&
+			now.Sub(manager.LastActivity()) > lobby.abandonedAfter
+
+		if !finished && !abandoned {
+			continue
+		}
+
+		manager.Terminate()
+		delete(lobby.games, id)
+		removed = append(removed, id)
+
+		log.Logger.Info(
+			"Removed game", "id", id, "finished", finished, "abandoned", abandoned,
+		)
+	}
+
+	return removed
+}
+
+// RunCleanup calls Cleanup every interval until stop is closed.
+func (lobby *Lobby) RunCleanup(interval time.Duration, stop <-chan struct{}) {
+	ticker := lobby.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C():
+			lobby.Cleanup(now)
+		case <-stop:
+			return
+		}
+	}
+}
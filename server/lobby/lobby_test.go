@@ -0,0 +1,137 @@
+package lobby
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/maria-mz/bash-battle-server/clock"
+	"github.com/maria-mz/bash-battle-server/config"
+	"github.com/maria-mz/bash-battle-server/game"
+	"github.com/maria-mz/bash-battle-server/game/gametest"
+	"github.com/maria-mz/bash-battle-server/log"
+	"github.com/maria-mz/bash-battle-server/server/network"
+	"github.com/stretchr/testify/assert"
+)
+
+var testConfig = config.GameConfig{
+	MaxPlayers:        2,
+	Rounds:            1,
+	RoundDuration:     2,
+	CountdownDuration: 1,
+}
+
+var testCatalog = game.NewCatalog(
+	game.Challenge{ID: "challenge-1", Question: "question-1"},
+	game.Challenge{ID: "challenge-2", Question: "question-2"},
+)
+
+func TestMain(m *testing.M) {
+	log.InitLogger()
+	m.Run()
+}
+
+func TestJoinOpenGame(t *testing.T) {
+	lobby := NewLobby(testCatalog, gametest.Verifier(t), testConfig, clock.Real())
+
+	clients := []*network.Client{
+		{Username: "player-1"},
+		{Username: "player-2"},
+		{Username: "player-3"},
+	}
+
+	for _, client := range clients {
+		_, err := lobby.JoinOpenGame(client)
+		assert.Nil(t, err)
+	}
+
+	// The first game fills up, so the third player gets a new one
+	assert.Len(t, lobby.ListGames(), 2)
+	assert.Equal(t, clients[0].GameID(), clients[1].GameID())
+	assert.NotEqual(t, clients[0].GameID(), clients[2].GameID())
+}
+
+func TestJoinGame(t *testing.T) {
+	lobby := NewLobby(testCatalog, gametest.Verifier(t), testConfig, clock.Real())
+
+	g, err := lobby.CreateGame(testConfig)
+	assert.Nil(t, err)
+
+	client := &network.Client{Username: "player-1"}
+
+	joined, err := lobby.JoinGame(g.ID, client)
+
+	assert.Nil(t, err)
+	assert.Equal(t, g, joined)
+	assert.Equal(t, g.ID, client.GameID())
+	assert.Equal(t, 1, g.Manager.NumPlayers())
+
+	_, err = lobby.JoinGame("nope", &network.Client{Username: "player-2"})
+	assert.ErrorIs(t, err, ErrGameNotFound)
+}
+
+func TestCreateGame_NotEnoughChallenges(t *testing.T) {
+	lobby := NewLobby(testCatalog, gametest.Verifier(t), testConfig, clock.Real())
+
+	conf := testConfig
+	conf.Rounds = 3
+
+	g, err := lobby.CreateGame(conf)
+
+	assert.Nil(t, g)
+	assert.ErrorIs(t, err, game.ErrNotEnoughChallenges)
+	assert.Empty(t, lobby.ListGames())
+}
+
+func TestCleanup(t *testing.T) {
+	clk := clock.NewFake(time.Now())
+	lobby := NewLobby(testCatalog, gametest.Verifier(t), testConfig, clk)
+	lobby.finishedGameTTL = time.Minute
+	lobby.abandonedAfter = time.Hour
+
+	active, _ := lobby.CreateGame(testConfig)
+	finished, _ := lobby.CreateGame(testConfig)
+
+	finished.Manager.Terminate()
+
+	removed := lobby.Cleanup(clk.Now())
+	assert.Empty(t, removed)
+
+	removed = lobby.Cleanup(clk.Now().Add(2 * time.Minute))
+	assert.Equal(t, []string{finished.ID}, removed)
+
+	_, ok := lobby.GetGame(active.ID)
+	assert.True(t, ok)
+
+	removed = lobby.Cleanup(clk.Now().Add(2 * time.Hour))
+	assert.Equal(t, []string{active.ID}, removed)
+	assert.True(t, active.Manager.IsOver())
+	assert.Empty(t, lobby.ListGames())
+}
+
+func TestJoinOpenGame_Concurrent(t *testing.T) {
+	lobby := NewLobby(testCatalog, gametest.Verifier(t), testConfig, clock.Real())
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := lobby.JoinOpenGame(&network.Client{Username: fmt.Sprintf("player-%d", i)})
+			assert.Nil(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	games := lobby.ListGames()
+	assert.Len(t, games, 15)
+
+	for _, g := range games {
+		assert.Equal(t, testConfig.MaxPlayers, g.Manager.NumPlayers())
+	}
+}
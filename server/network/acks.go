@@ -0,0 +1,158 @@
+package network
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/maria-mz/bash-battle-proto/proto"
+	"github.com/maria-mz/bash-battle-server/utils"
+)
+
+// How often a broadcast is resent to the clients that haven't acked it yet.
+const AckRetryInterval = 1 * time.Second
+
+// ackMatcher checks if a client message acks the broadcast waiting on it.
+type ackMatcher func(msg *pb.AckMsg) bool
+
+func isRoundLoaded(msg *pb.AckMsg) bool {
+	return msg.GetRoundLoaded() != nil
+}
+
+func isRoundSubmission(msg *pb.AckMsg) bool {
+	return msg.GetRoundSubmission() != nil
+}
+
+// submissionAck stands for a submission the game is done with.
+var submissionAck = &pb.AckMsg{Ack: &pb.AckMsg_RoundSubmission{RoundSubmission: &pb.RoundSubmission{}}}
+
+// ackTracker keeps track of the clients that have yet to ack a broadcast.
+type ackTracker struct {
+	matches ackMatcher
+	pending utils.Set[string]
+	done    chan struct{} // Closed once no client is pending
+	mu      sync.Mutex
+}
+
+func newAckTracker(matches ackMatcher, usernames []string) *ackTracker {
+	tracker := &ackTracker{
+		matches: matches,
+		pending: utils.NewSet[string](),
+		done:    make(chan struct{}),
+	}
+
+	for _, username := range usernames {
+		tracker.pending.Add(username)
+	}
+
+	if tracker.pending.Size() == 0 {
+		close(tracker.done)
+	}
+
+	return tracker
+}
+
+// ack records the client's message, if it is the ack being waited on.
+func (tracker *ackTracker) ack(username string, msg *pb.AckMsg) {
+	if tracker.matches(msg) {
+		tracker.drop(username)
+	}
+}
+
+// drop stops waiting on the client, e.g. because it disconnected.
+func (tracker *ackTracker) drop(username string) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	if !tracker.pending.Contains(username) {
+		return
+	}
+
+	tracker.pending.Delete(username)
+
+	if tracker.pending.Size() == 0 {
+		close(tracker.done)
+	}
+}
+
+func (tracker *ackTracker) isPending(username string) bool {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	return tracker.pending.Contains(username)
+}
+
+// stragglers returns the clients still pending, sorted.
+func (tracker *ackTracker) stragglers() []string {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	stragglers := tracker.pending.Items()
+	sort.Strings(stragglers)
+
+	return stragglers
+}
+
+// broadcastUntilAcked sends the event to every client with an active
+// stream, then resends it every AckRetryInterval to the clients that
+// haven't acked it, until they all have or the timeout elapses. Returns
+// the clients that never acked.
+func (net *Network) broadcastUntilAcked(event *pb.Event, matches ackMatcher, timeout time.Duration) []string {
+	var clients []*Client
+	var usernames []string
+
+	for _, client := range net.getClients() {
+		if client.activeStream() != nil {
+			clients = append(clients, client)
+			usernames = append(usernames, client.Username)
+		}
+	}
+
+	if len(clients) == 0 {
+		return nil
+	}
+
+	tracker := newAckTracker(matches, usernames)
+
+	net.setAckTracker(tracker)
+	defer net.setAckTracker(nil)
+
+	deadline := net.clock.NewTimer(timeout)
+	defer deadline.Stop()
+
+	retry := net.clock.NewTicker(AckRetryInterval)
+	defer retry.Stop()
+
+	for {
+		for _, client := range clients {
+			if tracker.isPending(client.Username) {
+				net.SendEventToClient(event, client)
+			}
+		}
+
+		select {
+		case <-tracker.done:
+			return nil
+
+		case <-retry.C():
+			continue
+
+		case <-deadline.C():
+			return tracker.stragglers()
+		}
+	}
+}
+
+func (net *Network) setAckTracker(tracker *ackTracker) {
+	net.mu.Lock()
+	defer net.mu.Unlock()
+
+	net.acks = tracker
+}
+
+func (net *Network) getAckTracker() *ackTracker {
+	net.mu.RLock()
+	defer net.mu.RUnlock()
+
+	return net.acks
+}
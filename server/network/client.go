@@ -1,19 +1,102 @@
 package network
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+)
 
-type ClientMeta struct {
-	Active bool
+// Client is a connected player. SessionID and Username never change; the rest
+// is only accessed through methods, as the client is shared between the
+// gRPC handlers and the game the client is in.
+type Client struct {
+	SessionID string // Shared by the tokens issued to the client
+	Username  string
+
+	gameID string  // Empty until the client joins a game
+	stream *Stream // The client's latest stream, if any
+	active bool    // Whether stream is being listened to
+
+	mu sync.Mutex
 }
 
-type Client struct {
-	Token    string
-	Username string
-	Stream   *Stream
+func (client *Client) GameID() string {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	return client.gameID
+}
+
+func (client *Client) SetGameID(gameID string) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	client.gameID = gameID
+}
+
+func (client *Client) Stream() *Stream {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	return client.stream
+}
+
+// SetStream makes stream the client's stream, and returns the stream it
+// replaces (nil if none).
+func (client *Client) SetStream(stream *Stream) *Stream {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	old := client.stream
+	client.stream = stream
+	client.active = false
+
+	return old
+}
+
+// ClearStream removes the client's stream, unless it has since been
+// replaced by another one.
+func (client *Client) ClearStream(stream *Stream) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if client.stream == stream {
+		client.stream = nil
+		client.active = false
+	}
+}
+
+// setActive marks the stream as listened to (or not), unless it has since
+// been replaced by another one.
+func (client *Client) setActive(stream *Stream, active bool) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if client.stream == stream {
+		client.active = active
+	}
+}
+
+// activeStream returns the client's stream if it is being listened to.
+func (client *Client) activeStream() *Stream {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if !client.active {
+		return nil
+	}
+
+	return client.stream
+}
+
+func (client *Client) String() string {
+	client.mu.Lock()
+	defer client.mu.Unlock()
 
-	meta ClientMeta // TODO: make public ? or read only
+	return fmt.Sprintf(
+		"{Username:%s GameID:%s Active:%t}", client.Username, client.gameID, client.active,
+	)
 }
 
 func (client *Client) InfoString() string {
-	return fmt.Sprintf("%+v", client)
+	return client.String()
 }
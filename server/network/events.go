@@ -54,13 +54,16 @@ func BuildRoundStartedEvent(round int, endsAt time.Time) *pb.Event {
 	return event
 }
 
-func BuildLoadRoundEvent(round int, challenge game.Challenge) *pb.Event {
+func BuildLoadRoundEvent(round int, challenge game.Challenge, files game.ChallengeFiles) *pb.Event {
 	event := &pb.Event{
 		Event: &pb.Event_LoadRound{
 			LoadRound: &pb.LoadRound{
-				RoundNumber: int32(round),
-				Question:    challenge.Question,
-				// TODO: Add files as bytes
+				RoundNumber:    int32(round),
+				Question:       challenge.Question,
+				InputFile:      files.Input,
+				OutputFile:     files.Output,
+				InputChecksum:  files.InputChecksum,
+				OutputChecksum: files.OutputChecksum,
 			},
 		},
 	}
@@ -76,12 +79,58 @@ func BuildSubmitRoundScoreEvent() *pb.Event {
 	return event
 }
 
-func BuildGameOverEvent() *pb.Event {
+func BuildSubmissionResultEvent(round int, accepted bool, won bool, reason string) *pb.Event {
+	event := &pb.Event{
+		Event: &pb.Event_SubmissionResult{
+			SubmissionResult: &pb.SubmissionResult{
+				RoundNumber: int32(round),
+				Accepted:    accepted,
+				Won:         won,
+				Reason:      reason,
+			},
+		},
+	}
+
+	return event
+}
+
+func BuildLeaderboardEvent(round int, standings []game.Standing) *pb.Event {
+	event := &pb.Event{
+		Event: &pb.Event_Leaderboard{
+			Leaderboard: &pb.Leaderboard{
+				RoundNumber: int32(round),
+				Standings:   standingsToProto(standings),
+			},
+		},
+	}
+
+	return event
+}
+
+func BuildGameOverEvent(standings []game.Standing) *pb.Event {
 	event := &pb.Event{
 		Event: &pb.Event_GameOver{
-			GameOver: &pb.GameOver{},
+			GameOver: &pb.GameOver{
+				Standings: standingsToProto(standings),
+				Winners:   game.Winners(standings),
+			},
 		},
 	}
 
 	return event
 }
+
+func standingsToProto(standings []game.Standing) []*pb.Standing {
+	protoStandings := make([]*pb.Standing, 0, len(standings))
+
+	for _, standing := range standings {
+		protoStandings = append(protoStandings, &pb.Standing{
+			Rank:      int32(standing.Rank),
+			Username:  standing.Player,
+			Points:    int32(standing.Points),
+			RoundsWon: int32(standing.RoundsWon),
+		})
+	}
+
+	return protoStandings
+}
@@ -1,6 +1,10 @@
 package network
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -62,18 +66,37 @@ func TestBuildRoundStartedEvent(t *testing.T) {
 }
 
 func TestBuildLoadRoundEvent(t *testing.T) {
+	dir := t.TempDir()
+	input := []byte("banana\napple\n")
+	output := []byte("apple\nbanana\n")
+
+	os.WriteFile(filepath.Join(dir, "input.txt"), input, 0o644)
+	os.WriteFile(filepath.Join(dir, "output.txt"), output, 0o644)
+
 	round := 1
 	challenge := game.Challenge{
-		Question: "sample-question",
-		// TODO: Add file paths and check bytes in pb message
+		Question:   "sample-question",
+		InputFile:  game.FilePath(filepath.Join(dir, "input.txt")),
+		OutputFile: game.FilePath(filepath.Join(dir, "output.txt")),
 	}
 
-	event := BuildLoadRoundEvent(round, challenge)
+	files, err := challenge.ReadFiles(1024)
+	assert.Nil(t, err)
+
+	event := BuildLoadRoundEvent(round, challenge, files)
 
 	assert.NotNil(t, event)
 	assert.NotNil(t, event.GetLoadRound())
 	assert.Equal(t, round, int(event.GetLoadRound().GetRoundNumber()))
 	assert.Equal(t, challenge.Question, event.GetLoadRound().Question)
+	assert.Equal(t, input, event.GetLoadRound().GetInputFile())
+	assert.Equal(t, output, event.GetLoadRound().GetOutputFile())
+
+	inputSum := sha256.Sum256(input)
+	outputSum := sha256.Sum256(output)
+
+	assert.Equal(t, hex.EncodeToString(inputSum[:]), event.GetLoadRound().GetInputChecksum())
+	assert.Equal(t, hex.EncodeToString(outputSum[:]), event.GetLoadRound().GetOutputChecksum())
 }
 
 func TestBuildSubmitRoundScoreEvent(t *testing.T) {
@@ -87,13 +110,51 @@ func TestBuildSubmitRoundScoreEvent(t *testing.T) {
 	assert.True(t, ok)
 }
 
-func TestBuildGameOverEvent(t *testing.T) {
-	event := BuildGameOverEvent()
+func TestBuildSubmissionResultEvent(t *testing.T) {
+	event := BuildSubmissionResultEvent(2, false, false, "too late")
 
 	assert.NotNil(t, event)
+	assert.NotNil(t, event.GetSubmissionResult())
+	assert.Equal(t, 2, int(event.GetSubmissionResult().GetRoundNumber()))
+	assert.False(t, event.GetSubmissionResult().GetAccepted())
+	assert.False(t, event.GetSubmissionResult().GetWon())
+	assert.Equal(t, "too late", event.GetSubmissionResult().GetReason())
+}
 
-	// Since message inside is empty assert.NotNil() will evaluate to false
-	// Validate type by type assertion instead
-	_, ok := event.GetEvent().(*proto.Event_GameOver)
-	assert.True(t, ok)
+func TestBuildLeaderboardEvent(t *testing.T) {
+	round := 2
+	standings := []game.Standing{
+		{Rank: 1, Player: "player-2", Points: 150, RoundsWon: 2},
+		{Rank: 2, Player: "player-1", Points: 80, RoundsWon: 1},
+	}
+
+	event := BuildLeaderboardEvent(round, standings)
+
+	assert.NotNil(t, event)
+	assert.NotNil(t, event.GetLeaderboard())
+	assert.Equal(t, round, int(event.GetLeaderboard().GetRoundNumber()))
+	assert.Equal(
+		t,
+		[]*proto.Standing{
+			{Rank: 1, Username: "player-2", Points: 150, RoundsWon: 2},
+			{Rank: 2, Username: "player-1", Points: 80, RoundsWon: 1},
+		},
+		event.GetLeaderboard().GetStandings(),
+	)
+}
+
+func TestBuildGameOverEvent(t *testing.T) {
+	standings := []game.Standing{
+		{Rank: 1, Player: "player-1", Points: 100, RoundsWon: 1},
+		{Rank: 1, Player: "player-2", Points: 100, RoundsWon: 1},
+		{Rank: 3, Player: "player-3", Points: 0, RoundsWon: 0},
+	}
+
+	event := BuildGameOverEvent(standings)
+
+	assert.NotNil(t, event)
+	assert.NotNil(t, event.GetGameOver())
+	assert.Len(t, event.GetGameOver().GetStandings(), 3)
+	assert.Equal(t, "player-3", event.GetGameOver().GetStandings()[2].GetUsername())
+	assert.Equal(t, []string{"player-1", "player-2"}, event.GetGameOver().GetWinners())
 }
@@ -2,15 +2,18 @@ package network
 
 import (
 	"errors"
+	"sync"
 	"time"
 
 	pb "github.com/maria-mz/bash-battle-proto/proto"
+	"github.com/maria-mz/bash-battle-server/clock"
 	"github.com/maria-mz/bash-battle-server/game"
 	"github.com/maria-mz/bash-battle-server/log"
 )
 
 var ErrUsernameTaken = errors.New("a player with this name already exists")
 var ErrClientNotFound = errors.New("client not found in game")
+var ErrNoStream = errors.New("client has no stream")
 
 type ClientMsg struct {
 	Username string
@@ -20,21 +23,36 @@ type ClientMsg struct {
 type Network struct {
 	clients    map[string]*Client
 	clientMsgs chan<- ClientMsg
+	ackTimeout time.Duration
+	clock      clock.Clock
+	acks       *ackTracker  // Set while a broadcast waits for acks
+	mu         sync.RWMutex // Guards clients and acks
+
+	done      chan struct{} // Closed once the network is closed
+	closeOnce sync.Once
 }
 
-func NewNetwork() (*Network, <-chan ClientMsg) {
+// NewNetwork creates a network whose broadcasts wait up to ackTimeout, as
+// measured by clk, for clients to ack them.
+func NewNetwork(ackTimeout time.Duration, clk clock.Clock) (*Network, <-chan ClientMsg) {
 	clients := make(map[string]*Client)
 	clientMsgs := make(chan ClientMsg)
 
 	net := &Network{
 		clients:    clients,
 		clientMsgs: clientMsgs,
+		ackTimeout: ackTimeout,
+		clock:      clk,
+		done:       make(chan struct{}),
 	}
 
 	return net, clientMsgs
 }
 
 func (net *Network) AddClient(client *Client) error {
+	net.mu.Lock()
+	defer net.mu.Unlock()
+
 	if _, ok := net.clients[client.Username]; ok {
 		return ErrUsernameTaken
 	} else {
@@ -43,21 +61,97 @@ func (net *Network) AddClient(client *Client) error {
 	}
 }
 
-func (net *Network) ListenForClientMsgs(username string) error {
+// RemoveClient stops sending events to the client. No-op if the client
+// isn't in the network.
+func (net *Network) RemoveClient(username string) {
+	net.mu.Lock()
+	defer net.mu.Unlock()
+
+	delete(net.clients, username)
+}
+
+func (net *Network) getClient(username string) (*Client, bool) {
+	net.mu.RLock()
+	defer net.mu.RUnlock()
+
 	client, ok := net.clients[username]
+	return client, ok
+}
+
+// getClients returns the clients as they are now, so events can be sent
+// without holding the lock.
+func (net *Network) getClients() []*Client {
+	net.mu.RLock()
+	defer net.mu.RUnlock()
+
+	clients := make([]*Client, 0, len(net.clients))
+
+	for _, client := range net.clients {
+		clients = append(clients, client)
+	}
+
+	return clients
+}
+
+// QueueStats returns the outbound queue stats of every client with a
+// stream.
+func (net *Network) QueueStats() map[string]QueueStats {
+	stats := make(map[string]QueueStats)
+
+	for _, client := range net.getClients() {
+		if stream := client.Stream(); stream != nil {
+			stats[client.Username] = stream.QueueStats()
+		}
+	}
+
+	return stats
+}
+
+// Close ends the stream of every client with err, so their handlers
+// return, and stops passing on client messages. Done is closed once it
+// has. Calling Close again only ends the streams.
+func (net *Network) Close(err error) {
+	for _, client := range net.getClients() {
+		if stream := client.Stream(); stream != nil {
+			stream.Close(err)
+		}
+	}
+
+	net.closeOnce.Do(func() { close(net.done) })
+}
+
+// Done is closed once the network is closed, after which no more client
+// messages are sent.
+func (net *Network) Done() <-chan struct{} {
+	return net.done
+}
+
+func (net *Network) ListenForClientMsgs(username string) error {
+	client, ok := net.getClient(username)
 
 	if !ok {
 		return ErrClientNotFound
 	}
 
-	go net.handleClientMsgs(client)
-	go client.Stream.Recv()
+	stream := client.Stream()
+
+	if stream == nil {
+		return ErrNoStream
+	}
+
+	go net.handleClientMsgs(client.Username, stream)
+	go stream.Recv()
 
-	client.meta.Active = true
+	client.setActive(stream, true)
 
-	msg := <-client.Stream.EndStreamMsgs // blocking
+	msg := <-stream.EndStreamMsgs // blocking
 
-	client.meta.Active = false
+	// A replaced stream must not mark the client's new stream inactive
+	client.setActive(stream, false)
+
+	if tracker := net.getAckTracker(); tracker != nil && client.activeStream() == nil {
+		tracker.drop(client.Username) // Not worth waiting on
+	}
 
 	if msg.Err != nil {
 		log.Logger.Warn(
@@ -72,21 +166,55 @@ func (net *Network) ListenForClientMsgs(username string) error {
 	return msg.Err
 }
 
-func (net *Network) handleClientMsgs(client *Client) {
-	for msg := range client.Stream.AckMsgs {
+func (net *Network) handleClientMsgs(username string, stream *Stream) {
+	for msg := range stream.AckMsgs {
 		switch msg.Ack.(type) {
 
 		case *pb.AckMsg_RoundLoaded:
-			log.Logger.Info("Client loaded round", "client", client.Username)
+			log.Logger.Info("Client loaded round", "client", username)
 
 		case *pb.AckMsg_RoundSubmission:
-			log.Logger.Info("Client made a submission", "client", client.Username)
+			log.Logger.Info("Client made a submission", "client", username)
+		}
+
+		// Submissions only count once the game is done with them, see
+		// AckSubmission
+		if tracker := net.getAckTracker(); tracker != nil && !isRoundSubmission(msg) {
+			tracker.ack(username, msg)
 		}
 
-		net.clientMsgs <- ClientMsg{client.Username, msg}
+		select {
+		case net.clientMsgs <- ClientMsg{username, msg}:
+		case <-net.done:
+			return // Nobody is reading anymore
+		}
 	}
 }
 
+// StartStream starts sending broadcasts to the client's stream, once the
+// snapshot events catching the client up on a game under way are queued.
+// Broadcasts can't get ahead of the snapshot, as long as the game doesn't
+// change while this runs.
+func (net *Network) StartStream(client *Client, snapshot []*pb.Event) {
+	stream := client.Stream()
+
+	if stream == nil {
+		return
+	}
+
+	if len(snapshot) > 0 {
+		log.Logger.Info(
+			"Sending game snapshot", "client", client.Username, "events", len(snapshot),
+		)
+	}
+
+	for _, event := range snapshot {
+		stream.SendEvent(event)
+	}
+
+	client.setActive(stream, true)
+}
+
 func (net *Network) BroadcastPlayerJoin(player *game.Player) {
 	log.Logger.Info(
 		"Broadcasting event PLAYER_JOIN", "player", player.InfoString(),
@@ -96,6 +224,15 @@ func (net *Network) BroadcastPlayerJoin(player *game.Player) {
 	net.BroadcastEvent(event)
 }
 
+func (net *Network) BroadcastPlayerLeave(player *game.Player) {
+	log.Logger.Info(
+		"Broadcasting event PLAYER_LEFT", "player", player.InfoString(),
+	)
+
+	event := BuildPlayerLeftEvent(player)
+	net.BroadcastEvent(event)
+}
+
 func (net *Network) BroadcastCountdown(round int, startsAt time.Time) {
 	log.Logger.Info(
 		"Broadcasting event COUNTING_DOWN",
@@ -118,73 +255,97 @@ func (net *Network) BroadcastRoundStart(round int, endsAt time.Time) {
 	net.BroadcastEvent(event)
 }
 
-func (net *Network) BroadcastGameOver() {
-	log.Logger.Info("Broadcasting event GAME_OVER")
-
-	event := BuildGameOverEvent()
-	net.BroadcastEvent(event)
-}
-
-func (net *Network) BroadcastLoadRound(round int, challenge game.Challenge, callback func()) {
-	net.broadcastMultipleTimes(
-		func() { net.broadcastLoadRound(round, challenge) },
+func (net *Network) BroadcastLeaderboard(round int, standings []game.Standing) {
+	log.Logger.Info(
+		"Broadcasting event LEADERBOARD", "round", round, "standings", standings,
 	)
 
-	callback()
+	event := BuildLeaderboardEvent(round, standings)
+	net.BroadcastEvent(event)
 }
 
-func (net *Network) BroadcastSubmitScore(round int, callback func()) {
-	net.broadcastMultipleTimes(
-		func() { net.broadcastSubmitScore(round) },
-	)
+func (net *Network) BroadcastGameOver(standings []game.Standing) {
+	log.Logger.Info("Broadcasting event GAME_OVER", "standings", standings)
 
-	callback()
+	event := BuildGameOverEvent(standings)
+	net.BroadcastEvent(event)
 }
 
-func (net *Network) broadcastLoadRound(round int, challenge game.Challenge) {
+// BroadcastLoadRound broadcasts the round until every client has acked it
+// with RoundLoaded, or the ack timeout elapses. callback gets the clients
+// that didn't ack.
+func (net *Network) BroadcastLoadRound(
+	round int, challenge game.Challenge, files game.ChallengeFiles, callback func(stragglers []string),
+) {
 	log.Logger.Info(
 		"Broadcasting event LOAD_ROUND",
 		"round", round,
 		"challenge", challenge.InfoString(),
 	)
 
-	event := BuildLoadRoundEvent(round, challenge)
-	net.BroadcastEvent(event)
+	event := BuildLoadRoundEvent(round, challenge, files)
+	callback(net.broadcastUntilAcked(event, isRoundLoaded, net.ackTimeout))
 }
 
-func (net *Network) broadcastSubmitScore(round int) {
-	log.Logger.Info("Broadcasting event SUBMIT_ROUND_SCORE", "round", round)
+// BroadcastSubmitScore broadcasts the end of the round until every client
+// has acked it with a RoundSubmission, or the submission window closes.
+// callback gets the clients that didn't submit.
+func (net *Network) BroadcastSubmitScore(round int, window time.Duration, callback func(stragglers []string)) {
+	log.Logger.Info("Broadcasting event SUBMIT_ROUND_SCORE", "round", round, "window", window)
 
 	event := BuildSubmitRoundScoreEvent()
-	net.BroadcastEvent(event)
+	callback(net.broadcastUntilAcked(event, isRoundSubmission, window))
 }
 
-func (net *Network) BroadcastEvent(event *pb.Event) {
-	for _, client := range net.clients {
-		net.SendEventToClient(event, client)
+// AckSubmission counts the client's submission as an ack of the
+// SubmitRoundScore broadcast, if it is waiting on one. Called once the
+// submission has been recorded or rejected, so the round isn't scored
+// before then.
+func (net *Network) AckSubmission(username string) {
+	if tracker := net.getAckTracker(); tracker != nil {
+		tracker.ack(username, submissionAck)
 	}
 }
 
-func (net *Network) broadcastMultipleTimes(broadcast func()) {
-	ticker := time.NewTicker(1 * time.Second) // TODO: Use constant
-	count := 0
+// SendSubmissionResult tells a client whether its submission was accepted.
+func (net *Network) SendSubmissionResult(username string, round int, accepted bool, won bool, reason string) {
+	client, ok := net.getClient(username)
 
-	for range ticker.C {
-		if count == 3 { // TODO: Use constant
-			return
-		}
+	if !ok {
+		return
+	}
 
-		broadcast()
-		count++
+	log.Logger.Info(
+		"Sending event SUBMISSION_RESULT",
+		"client", username,
+		"round", round,
+		"accepted", accepted,
+		"won", won,
+		"reason", reason,
+	)
+
+	event := BuildSubmissionResultEvent(round, accepted, won, reason)
+	net.SendEventToClient(event, client)
+}
+
+func (net *Network) BroadcastEvent(event *pb.Event) {
+	for _, client := range net.getClients() {
+		net.SendEventToClient(event, client)
 	}
 }
 
 func (net *Network) SendEventToClient(event *pb.Event, client *Client) {
-	if client.meta.Active {
+	if stream := client.activeStream(); stream != nil {
 		log.Logger.Info(
 			"Sent event to client", "client", client.Username,
 		)
-		client.Stream.SendEvent(event)
+
+		if stream.SendEvent(event) {
+			log.Logger.Warn(
+				"Client is not keeping up, dropping its oldest events",
+				"client", client.Username, "stats", stream.QueueStats(),
+			)
+		}
 	} else {
 		log.Logger.Info(
 			"Did not send event to client (stream is nil)",
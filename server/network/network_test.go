@@ -1,22 +1,28 @@
 package network
 
 import (
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/maria-mz/bash-battle-proto/proto"
+	"github.com/maria-mz/bash-battle-server/clock"
 	"github.com/maria-mz/bash-battle-server/game"
 	"github.com/maria-mz/bash-battle-server/log"
 	"github.com/maria-mz/bash-battle-server/utils"
 	"github.com/stretchr/testify/assert"
 )
 
+const testAckTimeout = 1500 * time.Millisecond
+
 func TestMain(m *testing.M) {
 	log.InitLogger()
 	m.Run()
 }
 
 func TestNewNetwork(t *testing.T) {
-	network, clientMsgs := NewNetwork()
+	network, clientMsgs := NewNetwork(testAckTimeout, clock.Real())
 
 	assert.NotNil(t, network)
 	assert.NotNil(t, clientMsgs)
@@ -24,7 +30,7 @@ func TestNewNetwork(t *testing.T) {
 }
 
 func TestAddClient_Ok(t *testing.T) {
-	network, _ := NewNetwork()
+	network, _ := NewNetwork(testAckTimeout, clock.Real())
 
 	c1 := &Client{Username: "player-1"}
 
@@ -35,7 +41,7 @@ func TestAddClient_Ok(t *testing.T) {
 }
 
 func TestAddClient_ErrNameTaken(t *testing.T) {
-	network, _ := NewNetwork()
+	network, _ := NewNetwork(testAckTimeout, clock.Real())
 
 	c1 := &Client{Username: "player-1"}
 	c2 := &Client{Username: "player-1"}
@@ -47,23 +53,29 @@ func TestAddClient_ErrNameTaken(t *testing.T) {
 	assert.Equal(t, network.clients[c1.Username], c1) // Ensure ptr is perserved
 }
 
+func newStreamingClient(username string, mss *utils.MockStreamServer) *Client {
+	return newQueuedClient(username, mss, DefaultQueueConfig())
+}
+
+func newQueuedClient(username string, mss *utils.MockStreamServer, queue QueueConfig) *Client {
+	client := &Client{Username: username}
+	stream := NewStream(mss, queue)
+
+	client.SetStream(stream)
+	client.setActive(stream, true)
+
+	return client
+}
+
 func TestBroadcast(t *testing.T) {
-	network, _ := NewNetwork()
+	network, _ := NewNetwork(testAckTimeout, clock.Real())
 
 	mss1 := utils.NewMockStreamServer()
 	mss2 := utils.NewMockStreamServer()
 
-	c1 := &Client{
-		Username: "player-1",
-		Stream:   NewStream(mss1),
-		meta:     ClientMeta{Active: true},
-	}
+	c1 := newStreamingClient("player-1", mss1)
 	c2 := &Client{Username: "player-2"} // No stream
-	c3 := &Client{
-		Username: "player-3",
-		Stream:   NewStream(mss2),
-		meta:     ClientMeta{Active: true},
-	}
+	c3 := newStreamingClient("player-3", mss2)
 
 	network.AddClient(c1)
 	network.AddClient(c2)
@@ -79,15 +91,11 @@ func TestBroadcast(t *testing.T) {
 }
 
 func TestClientAck(t *testing.T) {
-	network, clientMsgs := NewNetwork()
+	network, clientMsgs := NewNetwork(testAckTimeout, clock.Real())
 
 	mss := utils.NewMockStreamServer()
 
-	c := &Client{
-		Username: "player-1",
-		Stream:   NewStream(mss),
-		meta:     ClientMeta{Active: true},
-	}
+	c := newStreamingClient("player-1", mss)
 
 	go func() {
 		ackMsg := &proto.AckMsg{
@@ -109,3 +117,259 @@ func TestClientAck(t *testing.T) {
 	network.AddClient(c)
 	network.ListenForClientMsgs(c.Username) // Blocks until mss.Close() is called
 }
+
+func TestStreamClose(t *testing.T) {
+	network, _ := NewNetwork(testAckTimeout, clock.Real())
+
+	mss := utils.NewMockStreamServer()
+	c := newStreamingClient("player-1", mss)
+	stream := c.Stream()
+
+	network.AddClient(c)
+
+	go func() {
+		stream.Close(ErrStreamReplaced)
+		stream.Close(ErrStreamReplaced) // No-op
+	}()
+
+	err := network.ListenForClientMsgs(c.Username) // Blocks until closed
+
+	assert.ErrorIs(t, err, ErrStreamReplaced)
+
+	stream.SendEvent(&proto.Event{}) // Dropped, stream is closed
+	assert.Len(t, mss.RecievedEvents, 0)
+}
+
+func TestBroadcast_Concurrent(t *testing.T) {
+	network, _ := NewNetwork(testAckTimeout, clock.Real())
+
+	var wg sync.WaitGroup
+
+	// Few enough broadcasts that the mock streams never fill up
+	for i := 0; i < 5; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			mss := utils.NewMockStreamServer()
+			client := newStreamingClient(fmt.Sprintf("player-%d", i), mss)
+
+			network.AddClient(client)
+			network.BroadcastEvent(&proto.Event{})
+			network.RemoveClient(client.Username)
+		}()
+
+		go func() {
+			defer wg.Done()
+			network.BroadcastEvent(&proto.Event{})
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Empty(t, network.getClients())
+}
+
+// listen starts listening to a client's stream, forwarding nothing.
+func listen(t *testing.T, network *Network, clientMsgs <-chan ClientMsg, client *Client) {
+	go func() {
+		for range clientMsgs {
+		}
+	}()
+
+	go network.ListenForClientMsgs(client.Username)
+
+	assert.Eventually(t, func() bool { return client.activeStream() != nil }, time.Second, time.Millisecond)
+}
+
+func TestBroadcastLoadRound_WaitsForAcks(t *testing.T) {
+	network, clientMsgs := NewNetwork(testAckTimeout, clock.Real())
+
+	mss := utils.NewMockStreamServer()
+	c := &Client{Username: "player-1"}
+	c.SetStream(NewStream(mss, DefaultQueueConfig()))
+
+	network.AddClient(c)
+	listen(t, network, clientMsgs, c)
+
+	go func() {
+		<-mss.RecievedEvents
+		mss.AckMsgs <- &proto.AckMsg{Ack: &proto.AckMsg_RoundLoaded{RoundLoaded: &proto.RoundLoaded{}}}
+	}()
+
+	start := time.Now()
+
+	network.BroadcastLoadRound(1, game.Challenge{}, game.ChallengeFiles{}, func(stragglers []string) {
+		assert.Empty(t, stragglers)
+	})
+
+	assert.Less(t, time.Since(start), AckRetryInterval)
+}
+
+func TestBroadcastLoadRound_ReportsStragglers(t *testing.T) {
+	network, clientMsgs := NewNetwork(testAckTimeout, clock.Real())
+
+	mss := utils.NewMockStreamServer()
+	c := &Client{Username: "player-1"}
+	c.SetStream(NewStream(mss, DefaultQueueConfig()))
+
+	network.AddClient(c)
+	network.AddClient(&Client{Username: "player-2"}) // Not streaming, not waited on
+	listen(t, network, clientMsgs, c)
+
+	network.BroadcastLoadRound(1, game.Challenge{}, game.ChallengeFiles{}, func(stragglers []string) {
+		assert.Equal(t, []string{"player-1"}, stragglers)
+	})
+
+	// Sent once, then retried once before timing out
+	assert.Eventually(t, func() bool { return len(mss.RecievedEvents) == 2 }, time.Second, time.Millisecond)
+}
+
+func TestBroadcastSubmitScore_WaitsForTheGame(t *testing.T) {
+	network, clientMsgs := NewNetwork(testAckTimeout, clock.Real())
+
+	mss := utils.NewMockStreamServer()
+	c := &Client{Username: "player-1"}
+	c.SetStream(NewStream(mss, DefaultQueueConfig()))
+
+	network.AddClient(c)
+
+	go network.ListenForClientMsgs(c.Username)
+	assert.Eventually(t, func() bool { return c.activeStream() != nil }, time.Second, time.Millisecond)
+
+	done := make(chan []string)
+
+	go network.BroadcastSubmitScore(1, time.Minute, func(stragglers []string) {
+		done <- stragglers
+	})
+
+	<-mss.RecievedEvents
+	mss.AckMsgs <- &proto.AckMsg{Ack: &proto.AckMsg_RoundSubmission{RoundSubmission: &proto.RoundSubmission{}}}
+
+	// The submission arrived, but the game hasn't recorded it yet
+	<-clientMsgs
+
+	select {
+	case <-done:
+		t.Fatal("round scored before the submission was recorded")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	network.AckSubmission(c.Username)
+
+	assert.Empty(t, <-done)
+}
+
+func TestStream_DropOldest(t *testing.T) {
+	mss := utils.NewMockStreamServer() // Nobody reads, fills up after 10 events
+	stream := NewStream(mss, QueueConfig{Size: 2, OnOverflow: DropOldest})
+
+	for round := 1; round <= 20; round++ {
+		stream.SendEvent(BuildCountingDownEvent(round, time.Now()))
+	}
+
+	stats := stream.QueueStats()
+
+	assert.Greater(t, stats.Dropped, 0)
+	assert.LessOrEqual(t, stats.MaxDepth, 2)
+
+	// The newest events are the ones kept
+	var last *proto.Event
+
+	for {
+		select {
+		case last = <-mss.RecievedEvents:
+			continue
+		case <-time.After(100 * time.Millisecond):
+		}
+		break
+	}
+
+	assert.Equal(t, int32(20), last.GetCountingDown().GetRoundNumber())
+}
+
+func TestOutbox_ReportsOverflowOnce(t *testing.T) {
+	box := newOutbox(QueueConfig{Size: 2, OnOverflow: DropOldest})
+
+	var overflows []bool
+
+	for i := 0; i < 4; i++ {
+		_, overflowed := box.push(&proto.Event{})
+		overflows = append(overflows, overflowed)
+	}
+
+	assert.Equal(t, []bool{false, false, true, false}, overflows)
+
+	// Once drained, the next overflow is reported again
+	box.popAll()
+
+	box.push(&proto.Event{})
+	box.push(&proto.Event{})
+	_, overflowed := box.push(&proto.Event{})
+
+	assert.True(t, overflowed)
+	assert.Equal(t, 3, box.getStats().Dropped)
+}
+
+func TestStream_DisconnectSlowConsumer(t *testing.T) {
+	mss := utils.NewMockStreamServer() // Nobody reads, fills up after 10 events
+	stream := NewStream(mss, QueueConfig{Size: 1, OnOverflow: DisconnectSlowConsumer})
+
+	for round := 1; round <= 20; round++ {
+		stream.SendEvent(BuildCountingDownEvent(round, time.Now()))
+	}
+
+	msg := <-stream.EndStreamMsgs
+
+	assert.ErrorIs(t, msg.Err, ErrSlowConsumer)
+}
+
+func TestBroadcast_SlowClientDoesNotBlock(t *testing.T) {
+	network, _ := NewNetwork(testAckTimeout, clock.Real())
+
+	slow := newQueuedClient("slow", utils.NewMockStreamServer(), QueueConfig{Size: 5}) // Never read
+	fastMss := utils.NewMockStreamServer()
+	fast := newQueuedClient("fast", fastMss, QueueConfig{Size: 100})
+
+	network.AddClient(slow)
+	network.AddClient(fast)
+
+	received := make(chan int)
+
+	go func() {
+		count := 0
+		for range fastMss.RecievedEvents {
+			count++
+			if count == 30 {
+				received <- count
+			}
+		}
+	}()
+
+	for i := 0; i < 30; i++ {
+		network.BroadcastEvent(&proto.Event{})
+	}
+
+	select {
+	case count := <-received:
+		assert.Equal(t, 30, count)
+	case <-time.After(time.Second):
+		t.Fatal("broadcast blocked on the slow client")
+	}
+
+	assert.Greater(t, network.QueueStats()["slow"].Dropped, 0)
+}
+
+func TestParseOverflowPolicy(t *testing.T) {
+	policy, err := ParseOverflowPolicy("disconnect")
+	assert.Nil(t, err)
+	assert.Equal(t, DisconnectSlowConsumer, policy)
+
+	policy, err = ParseOverflowPolicy("")
+	assert.Nil(t, err)
+	assert.Equal(t, DropOldest, policy)
+
+	_, err = ParseOverflowPolicy("panic")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,145 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/maria-mz/bash-battle-proto/proto"
+)
+
+// DefaultQueueSize is how many events can wait to be sent to a client when
+// no other size is configured.
+const DefaultQueueSize = 64
+
+var ErrSlowConsumer = errors.New("client is not keeping up with events")
+
+// OverflowPolicy decides what happens when an event is sent to a client
+// whose queue is full.
+type OverflowPolicy int
+
+const (
+	// DropOldest drops the oldest queued event to make room.
+	DropOldest OverflowPolicy = iota
+
+	// DisconnectSlowConsumer ends the client's stream with ErrSlowConsumer.
+	DisconnectSlowConsumer
+)
+
+var overflowPolicies = map[string]OverflowPolicy{
+	"dropOldest": DropOldest,
+	"disconnect": DisconnectSlowConsumer,
+}
+
+// ParseOverflowPolicy returns the policy with the given name. An empty name
+// selects DropOldest.
+func ParseOverflowPolicy(name string) (OverflowPolicy, error) {
+	if name == "" {
+		return DropOldest, nil
+	}
+
+	policy, ok := overflowPolicies[name]
+
+	if !ok {
+		return 0, fmt.Errorf("unknown overflow policy %q", name)
+	}
+
+	return policy, nil
+}
+
+// QueueConfig sets up the outbound queue of a stream.
+type QueueConfig struct {
+	Size       int
+	OnOverflow OverflowPolicy
+}
+
+func DefaultQueueConfig() QueueConfig {
+	return QueueConfig{Size: DefaultQueueSize, OnOverflow: DropOldest}
+}
+
+// QueueStats describe a stream's outbound queue.
+type QueueStats struct {
+	Depth    int // Events waiting to be sent
+	MaxDepth int // Highest Depth seen
+	Sent     int
+	Dropped  int
+}
+
+// outbox is a bounded queue of events waiting to be sent.
+type outbox struct {
+	config   QueueConfig
+	events   []*proto.Event
+	ready    chan struct{} // Signalled when events are added
+	stats    QueueStats
+	dropping bool // Events were dropped since the queue was last drained
+	mu       sync.Mutex
+}
+
+func newOutbox(config QueueConfig) *outbox {
+	if config.Size <= 0 {
+		config.Size = DefaultQueueSize
+	}
+
+	return &outbox{
+		config: config,
+		ready:  make(chan struct{}, 1),
+	}
+}
+
+// push queues the event. ok is false if the queue was full and the policy
+// is to disconnect. overflowed is true if the queue started dropping events
+// to make room, and stays false while it keeps dropping them.
+func (box *outbox) push(event *proto.Event) (ok bool, overflowed bool) {
+	box.mu.Lock()
+	defer box.mu.Unlock()
+
+	if len(box.events) == box.config.Size {
+		if box.config.OnOverflow == DisconnectSlowConsumer {
+			return false, false
+		}
+
+		box.events = box.events[1:]
+		box.stats.Dropped++
+
+		overflowed = !box.dropping
+		box.dropping = true
+	}
+
+	box.events = append(box.events, event)
+	box.stats.Depth = len(box.events)
+	box.stats.MaxDepth = max(box.stats.MaxDepth, box.stats.Depth)
+
+	select {
+	case box.ready <- struct{}{}:
+	default: // Already signalled
+	}
+
+	return true, overflowed
+}
+
+// popAll takes every queued event, oldest first.
+func (box *outbox) popAll() []*proto.Event {
+	box.mu.Lock()
+	defer box.mu.Unlock()
+
+	events := box.events
+	box.events = nil
+	box.stats.Depth = 0
+	box.dropping = false
+
+	return events
+}
+
+func (box *outbox) markSent() {
+	box.mu.Lock()
+	defer box.mu.Unlock()
+
+	box.stats.Sent++
+}
+
+func (box *outbox) getStats() QueueStats {
+	box.mu.Lock()
+	defer box.mu.Unlock()
+
+	return box.stats
+}
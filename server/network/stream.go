@@ -1,11 +1,16 @@
 package network
 
 import (
+	"errors"
 	"io"
+	"sync"
 
 	"github.com/maria-mz/bash-battle-proto/proto"
+	"github.com/maria-mz/bash-battle-server/log"
 )
 
+var ErrStreamReplaced = errors.New("stream replaced by a newer stream")
+
 type SimpleStreamServer interface {
 	Send(*proto.Event) error
 	Recv() (*proto.AckMsg, error)
@@ -16,27 +21,40 @@ type EndStreamMsgs struct {
 	Err  error
 }
 
+// Stream is a client's connection. Events sent on it are queued, and sent
+// in order by the stream's own goroutine, so a slow client doesn't hold
+// up anyone else.
 type Stream struct {
 	streamSrv SimpleStreamServer
-	done      bool
+	outbox    *outbox
+	closed    chan struct{}
+	closeOnce sync.Once
 
 	AckMsgs       chan *proto.AckMsg
 	EndStreamMsgs chan EndStreamMsgs
 }
 
-func NewStream(streamSrv SimpleStreamServer) *Stream {
-	return &Stream{
+func NewStream(streamSrv SimpleStreamServer, queue QueueConfig) *Stream {
+	s := &Stream{
 		streamSrv:     streamSrv,
+		outbox:        newOutbox(queue),
+		closed:        make(chan struct{}),
 		AckMsgs:       make(chan *proto.AckMsg),
-		EndStreamMsgs: make(chan EndStreamMsgs),
+		EndStreamMsgs: make(chan EndStreamMsgs, 1),
 	}
+
+	go s.sendQueued()
+
+	return s
 }
 
 func (s *Stream) Recv() {
-	if s.done {
+	if s.isClosed() {
 		return
 	}
 
+	defer close(s.AckMsgs)
+
 	for {
 		msg, err := s.streamSrv.Recv()
 
@@ -50,23 +68,82 @@ func (s *Stream) Recv() {
 			return
 		}
 
-		s.AckMsgs <- msg
+		select {
+		case s.AckMsgs <- msg:
+		case <-s.closed:
+			return
+		}
 	}
 }
 
-func (s *Stream) SendEvent(event *proto.Event) {
-	if s.done {
-		return
+// SendEvent queues the event to be sent. If the queue is full, the
+// stream's overflow policy applies. Returns true if the queue just started
+// dropping events.
+func (s *Stream) SendEvent(event *proto.Event) (overflowed bool) {
+	if s.isClosed() {
+		return false
+	}
+
+	ok, overflowed := s.outbox.push(event)
+
+	if !ok {
+		log.Logger.Warn("Disconnecting slow client", "stats", s.outbox.getStats())
+		s.closeStream(EndStreamMsgs{Err: ErrSlowConsumer})
+	}
+
+	return overflowed
+}
+
+// QueueStats returns the state of the stream's outbound queue.
+func (s *Stream) QueueStats() QueueStats {
+	return s.outbox.getStats()
+}
+
+func (s *Stream) sendQueued() {
+	for {
+		select {
+		case <-s.outbox.ready:
+		case <-s.closed:
+			return
+		}
+
+		for _, event := range s.outbox.popAll() {
+			if s.isClosed() {
+				return
+			}
+
+			if err := s.streamSrv.Send(event); err != nil {
+				s.closeStream(EndStreamMsgs{Err: err})
+				return
+			}
+
+			s.outbox.markSent()
+		}
 	}
+}
+
+// Close ends the stream with err, as if the client had dropped it. Used to
+// tear down a stale stream when the client opens a new one, or one the
+// server is done with. No-op if the stream has ended already.
+func (s *Stream) Close(err error) {
+	s.closeStream(EndStreamMsgs{Err: err})
+}
 
-	if err := s.streamSrv.Send(event); err != nil {
-		s.closeStream(EndStreamMsgs{Err: err})
+func (s *Stream) isClosed() bool {
+	select {
+	case <-s.closed:
+		return true
+	default:
+		return false
 	}
 }
 
+// closeStream ends the stream. Only the first call has any effect, so the
+// stream can be closed by the client and by the server at the same time.
 func (s *Stream) closeStream(msg EndStreamMsgs) {
-	s.EndStreamMsgs <- msg
-	close(s.EndStreamMsgs)
-	close(s.AckMsgs)
-	s.done = true
+	s.closeOnce.Do(func() {
+		s.EndStreamMsgs <- msg
+		close(s.EndStreamMsgs)
+		close(s.closed)
+	})
 }
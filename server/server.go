@@ -2,97 +2,433 @@ package server
 
 import (
 	"errors"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/maria-mz/bash-battle-proto/proto"
+	"github.com/maria-mz/bash-battle-server/auth"
+	"github.com/maria-mz/bash-battle-server/clock"
 	"github.com/maria-mz/bash-battle-server/config"
+	"github.com/maria-mz/bash-battle-server/game"
 	"github.com/maria-mz/bash-battle-server/log"
 	"github.com/maria-mz/bash-battle-server/server/game_manager"
+	"github.com/maria-mz/bash-battle-server/server/lobby"
 	"github.com/maria-mz/bash-battle-server/server/network"
+	"github.com/maria-mz/bash-battle-server/username"
 	"github.com/maria-mz/bash-battle-server/utils"
 )
 
 var ErrTokenNotRecognized = errors.New("token not recognized")
-var ErrStreamAlreadyActive = errors.New("stream is already active")
 var ErrUsernameTaken = errors.New("a player with this name already exists")
+var ErrNotInGame = errors.New("not in a game")
+var ErrAlreadyInGame = errors.New("already in a game")
+var ErrLogoutInGame = errors.New("cannot log out during a game")
+
+// SessionCleanupInterval is how often sessions whose token expired are
+// disconnected.
+const SessionCleanupInterval = 1 * time.Minute
+
+// QueueStatsInterval is how often the outbound queues of clients who are
+// falling behind are logged.
+const QueueStatsInterval = 1 * time.Minute
+
+// session is a connected client, and the claims of the latest token issued
+// to it. Older tokens of the session are revoked.
+type session struct {
+	client *network.Client
+	claims auth.Claims
+}
 
 type Server struct {
+	catalog     *game.Catalog
+	lobby       *lobby.Lobby
+	issuer      *auth.Issuer
+	clock       clock.Clock // Times tokens and the periodic cleanups
+	stopCleanup chan struct{}
+
 	config       config.Config
-	clients      map[string]*network.Client
-	usernamePool utils.Set[string]
-	gameManager  *game_manager.GameManager
+	queue        network.QueueConfig
+	usernames    *username.Policy
+	sessions     map[string]*session // By session ID
+	usernamePool utils.Set[string]   // Keys of the usernames taken, see username.Key
+	mu           sync.Mutex          // Guards config, queue, usernames, sessions and usernamePool
+
+	// Held while a client joins a game, so a client can't join two at once
+	joinMu sync.Mutex
 }
 
-func NewServer(config config.Config) *Server {
-	return &Server{
+// NewServer creates a server for the config, after filling in its defaults.
+// Every game checks submissions with verifier. Fails if the config is
+// invalid or the catalog can't serve its games.
+func NewServer(config config.Config, catalog *game.Catalog, verifier *game.Verifier) (*Server, error) {
+	return newServer(config, catalog, verifier, clock.Real())
+}
+
+func newServer(config config.Config, catalog *game.Catalog, verifier *game.Verifier, clk clock.Clock) (*Server, error) {
+	config.ApplyDefaults()
+
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+
+	if err := catalog.CanServe(config.GameConfig); err != nil {
+		return nil, err
+	}
+
+	onOverflow, _ := network.ParseOverflowPolicy(config.Stream.OnOverflow) // Checked above
+
+	key := []byte(config.Auth.Secret)
+
+	if len(key) == 0 {
+		var err error
+
+		if key, err = auth.NewKey(); err != nil {
+			return nil, err
+		}
+
+		log.Logger.Warn("No auth secret configured, tokens won't survive a restart")
+	}
+
+	issuer := auth.NewIssuer(key, config.Auth.GetTokenTTL(), clk)
+
+	// Otherwise a token revoked before a restart would be valid again
+	if config.Auth.Secret != "" {
+		if err := issuer.PersistRevocations(config.Auth.RevokedFile); err != nil {
+			return nil, fmt.Errorf("cannot load revoked tokens: %w", err)
+		}
+	}
+
+	s := &Server{
+		catalog:      catalog,
 		config:       config,
-		clients:      make(map[string]*network.Client),
+		queue:        network.QueueConfig{Size: config.Stream.QueueSize, OnOverflow: onOverflow},
+		usernames:    username.NewPolicy(config.Usernames),
+		sessions:     make(map[string]*session),
 		usernamePool: utils.NewSet[string](),
-		gameManager:  game_manager.NewGameManager(config.GameConfig),
+		lobby:        lobby.NewLobby(catalog, verifier, config.GameConfig, clk),
+		issuer:       issuer,
+		clock:        clk,
+		stopCleanup:  make(chan struct{}),
 	}
+
+	go s.lobby.RunCleanup(lobby.CleanupInterval, s.stopCleanup)
+	go s.runSessionCleanup(SessionCleanupInterval, s.stopCleanup)
+	go s.runQueueStatsLog(QueueStatsInterval, s.stopCleanup)
+
+	return s, nil
+}
+
+// validateConfig also checks the fields only other packages know the valid
+// values of.
+func validateConfig(conf config.Config) error {
+	errs := []error{conf.Validate()}
+
+	if _, err := game.NewScoringStrategy(conf.GameConfig.Scoring); err != nil {
+		errs = append(errs, &config.FieldError{Path: "gameConfig.scoring", Problem: err.Error()})
+	}
+
+	if _, err := network.ParseOverflowPolicy(conf.Stream.OnOverflow); err != nil {
+		errs = append(errs, &config.FieldError{Path: "stream.onOverflow", Problem: err.Error()})
+	}
+
+	return errors.Join(errs...)
+}
+
+// Reload applies a new config to games created from now on, and to streams
+// opened and usernames checked from now on. Games in progress keep the
+// config they started with.
+// Host, port, challengesDir, tls and auth only change on restart
+// (certificate files are reloaded by the service).
+func (s *Server) Reload(conf config.Config) error {
+	conf.ApplyDefaults()
+
+	if err := validateConfig(conf); err != nil {
+		return err
+	}
+
+	if err := s.catalog.CanServe(conf.GameConfig); err != nil {
+		return err
+	}
+
+	onOverflow, _ := network.ParseOverflowPolicy(conf.Stream.OnOverflow)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if conf.Host != s.config.Host || conf.Port != s.config.Port ||
+		conf.ChallengesDir != s.config.ChallengesDir || conf.TLS != s.config.TLS ||
+		conf.Auth != s.config.Auth {
+		log.Logger.Warn("Host, port, challengesDir, tls and auth changes need a restart, keeping the current ones")
+
+		conf.Host = s.config.Host
+		conf.Port = s.config.Port
+		conf.ChallengesDir = s.config.ChallengesDir
+		conf.TLS = s.config.TLS
+		conf.Auth = s.config.Auth
+	}
+
+	s.config = conf
+	s.queue = network.QueueConfig{Size: conf.Stream.QueueSize, OnOverflow: onOverflow}
+	s.usernames = username.NewPolicy(conf.Usernames)
+	s.lobby.SetDefaultConfig(conf.GameConfig)
+
+	return nil
+}
+
+// Config returns the config the server is running with.
+func (s *Server) Config() config.Config {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.config
+}
+
+func (s *Server) Shutdown() {
+	close(s.stopCleanup)
 }
 
 func (s *Server) Connect(request *proto.ConnectRequest) (*proto.ConnectResponse, error) {
 	log.Logger.Info("New connect request", "request", request)
 
-	if s.usernamePool.Contains(request.Username) {
+	s.mu.Lock()
+
+	name, err := s.usernames.Check(request.Username)
+
+	if err != nil {
+		s.mu.Unlock()
+		log.Logger.Warn("Connect failed", "err", err)
+		return nil, err
+	}
+
+	// Names that look alike can't be connected at once
+	if s.usernamePool.Contains(username.Key(name)) {
+		s.mu.Unlock()
 		log.Logger.Warn("Connect failed", "err", ErrUsernameTaken)
 		return nil, ErrUsernameTaken
 	}
 
-	token := utils.GenerateToken()
-
 	client := &network.Client{
-		Token:    token,
-		Username: request.Username,
+		SessionID: utils.GenerateSessionID(),
+		Username:  name,
 	}
 
-	s.clients[client.Token] = client
-	s.usernamePool.Add(request.Username)
+	token, claims, err := s.issuer.Issue(client.SessionID, client.Username, "")
+
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+
+	s.sessions[client.SessionID] = &session{client: client, claims: claims}
+	s.usernamePool.Add(username.Key(name))
+
+	s.mu.Unlock()
 
 	log.Logger.Info("Connected new client", "client", client)
 
 	return &proto.ConnectResponse{Token: token}, nil
 }
 
-func (s *Server) JoinGame(token string) error {
-	log.Logger.Info("New join game request")
+// RefreshToken returns a new token for the session of a valid token, which
+// is revoked. The new token holds the game the client is in now.
+func (s *Server) RefreshToken(client *network.Client, token string) (*proto.ConnectResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[client.SessionID]
+
+	if !ok || sess.client != client {
+		return nil, ErrTokenNotRecognized
+	}
+
+	refreshed, claims, err := s.issuer.Refresh(token, client.GameID())
+
+	if err != nil {
+		return nil, err
+	}
+
+	sess.claims = claims
+
+	log.Logger.Debug("Refreshed token", "client", client.Username, "expiresAt", claims.ExpiresAt)
+
+	return &proto.ConnectResponse{Token: refreshed}, nil
+}
+
+// Logout revokes the client's token and disconnects them, freeing their
+// username. Players can't log out of a game that isn't over.
+func (s *Server) Logout(client *network.Client) error {
+	log.Logger.Info("New logout request", "client", client.Username)
+
+	if g, err := s.getGame(client); err == nil && !g.Manager.IsOver() {
+		return ErrLogoutInGame
+	}
+
+	s.disconnect(client)
+
+	return nil
+}
+
+func (s *Server) CreateGame(client *network.Client, gameConfig *proto.GameConfig) (*proto.GameInfo, error) {
+	log.Logger.Info("New create game request", "client", client.Username, "config", gameConfig)
+
+	defaultConfig := s.lobby.DefaultConfig()
+	merged := defaultConfig.Override(gameConfig)
+
+	if err := merged.Validate(); err != nil {
+		log.Logger.Warn("Rejected game config", "err", err)
+		return nil, err
+	}
+
+	g, err := s.lobby.CreateGame(merged)
+
+	if err != nil {
+		log.Logger.Warn("Failed to create game", "err", err)
+		return nil, err
+	}
+
+	return g.ToProto(), nil
+}
+
+func (s *Server) ListGames(client *network.Client) (*proto.GameInfos, error) {
+	games := s.lobby.ListGames()
+	gameInfos := &proto.GameInfos{Games: make([]*proto.GameInfo, 0, len(games))}
+
+	for _, g := range games {
+		gameInfos.Games = append(gameInfos.Games, g.ToProto())
+	}
+
+	return gameInfos, nil
+}
+
+// JoinGame adds the client to the game matching gameID. With no gameID,
+// the client joins any game waiting for players (or a new one). Joining
+// the game the client just finished opts them into a rematch.
+func (s *Server) JoinGame(client *network.Client, gameID string) error {
+	log.Logger.Info("New join game request", "client", client.Username, "gameID", gameID)
 
-	client, ok := s.clients[token]
+	s.joinMu.Lock()
+	defer s.joinMu.Unlock()
 
-	if !ok {
-		log.Logger.Info("Failed to join game", "err", ErrTokenNotRecognized)
-		return ErrTokenNotRecognized
+	oldGame, err := s.getGame(client)
+
+	if err == nil {
+		if oldGame.Manager.State() == game_manager.Done && gameID == oldGame.ID {
+			return oldGame.Manager.Rematch(client.Username)
+		}
+
+		if !oldGame.Manager.IsOver() {
+			log.Logger.Warn("Failed to join game", "client", client, "err", ErrAlreadyInGame)
+			return ErrAlreadyInGame
+		}
+	}
+
+	// The finished game must not reach the client anymore, nor take its
+	// stream down when it is cleaned up
+	if oldGame != nil {
+		if err := oldGame.Manager.Leave(client.Username); err != nil {
+			log.Logger.Warn("Failed to leave finished game", "client", client, "err", err)
+		}
 	}
 
-	err := s.gameManager.AddClient(client)
+	var g *lobby.Game
+
+	if gameID == "" {
+		g, err = s.lobby.JoinOpenGame(client)
+	} else {
+		g, err = s.lobby.JoinGame(gameID, client)
+	}
 
 	if err != nil {
 		log.Logger.Warn("Failed to join game", "client", client, "err", err)
 		return err
 	}
 
-	log.Logger.Info("Client joined game", "client", client)
+	log.Logger.Info("Client joined game", "client", client, "gameID", g.ID)
 
 	return nil
 }
 
-func (s *Server) GetGameConfig(token string) (*proto.GameConfig, error) {
-	_, ok := s.clients[token]
-	if !ok {
+// Authenticate verifies the token and returns the client of its session.
+// A valid token whose session isn't known, as after a restart, brings the
+// session back, and the client back into their game if it still exists.
+func (s *Server) Authenticate(token string) (*network.Client, error) {
+	claims, err := s.issuer.Verify(token)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sess, ok := s.sessions[claims.SessionID]; ok {
+		return sess.client, nil
+	}
+
+	// Someone else has taken the name since
+	if s.usernamePool.Contains(username.Key(claims.Username)) {
 		return nil, ErrTokenNotRecognized
 	}
 
-	return s.config.GameConfig.ToProto(), nil
+	client := &network.Client{
+		SessionID: claims.SessionID,
+		Username:  claims.Username,
+	}
+
+	if g, ok := s.lobby.GetGame(claims.GameID); ok && g.Manager.HasPlayer(client.Username) {
+		client.SetGameID(g.ID)
+	}
+
+	s.sessions[client.SessionID] = &session{client: client, claims: claims}
+	s.usernamePool.Add(username.Key(client.Username))
+
+	log.Logger.Info("Restored client session", "client", client)
+
+	return client, nil
 }
 
-func (s *Server) GetPlayers(token string) (*proto.Players, error) {
-	_, ok := s.clients[token]
-	if !ok {
-		return nil, ErrTokenNotRecognized
+// getGame returns the game the client is in.
+func (s *Server) getGame(client *network.Client) (*lobby.Game, error) {
+	gameID := client.GameID()
+
+	if gameID == "" {
+		return nil, ErrNotInGame
 	}
 
-	players := s.gameManager.GetPlayers()
-	protoPlayers := &proto.Players{Players: make([]*proto.Player, len(players))}
+	g, ok := s.lobby.GetGame(gameID)
+
+	// The client may have been left out of a rematch
+	if !ok || !g.Manager.HasPlayer(client.Username) {
+		return nil, ErrNotInGame
+	}
+
+	return g, nil
+}
+
+func (s *Server) GetGameConfig(client *network.Client) (*proto.GameConfig, error) {
+	g, err := s.getGame(client)
+
+	if err != nil {
+		// Not in a game yet, this is what a new game would use
+		defaultConfig := s.lobby.DefaultConfig()
+		return defaultConfig.ToProto(), nil
+	}
+
+	gameConfig := g.Manager.GetConfig()
+
+	return gameConfig.ToProto(), nil
+}
+
+func (s *Server) GetPlayers(client *network.Client) (*proto.Players, error) {
+	g, err := s.getGame(client)
+
+	if err != nil {
+		return nil, err
+	}
+
+	players := g.Manager.GetPlayers()
+	protoPlayers := &proto.Players{Players: make([]*proto.Player, 0, len(players))}
 
 	for _, player := range players {
 		protoPlayers.Players = append(protoPlayers.Players, player.ToProto())
@@ -101,20 +437,133 @@ func (s *Server) GetPlayers(token string) (*proto.Players, error) {
 	return protoPlayers, nil
 }
 
-func (s *Server) Stream(token string, streamSrv proto.BashBattle_StreamServer) error {
-	client, ok := s.clients[token]
-	if !ok {
-		return ErrTokenNotRecognized
+func (s *Server) Stream(client *network.Client, streamSrv proto.BashBattle_StreamServer) error {
+	g, err := s.getGame(client)
+
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	queue := s.queue
+	s.mu.Unlock()
+
+	stream := network.NewStream(streamSrv, queue)
+
+	// The client is reconnecting, the old stream is stale
+	if oldStream := client.SetStream(stream); oldStream != nil {
+		log.Logger.Info("Replacing stream", "client", client.Username)
+		oldStream.Close(network.ErrStreamReplaced)
 	}
 
-	if client.Stream != nil {
-		return ErrStreamAlreadyActive
+	err = g.Manager.ListenForClientMsgs(client) // Blocking
+
+	if errors.Is(err, network.ErrStreamReplaced) {
+		return err
 	}
 
-	stream := network.NewStream(streamSrv)
-	client.Stream = stream
+	client.ClearStream(stream)
+	stream.Close(err) // Stops its sender, if it didn't end on its own
 
-	err := s.gameManager.ListenForClientMsgs(client) // Blocking
+	// Players who leave the lobby are dropped from the game, let someone
+	// else have their name. Players who moved on to another game are kept
+	if _, err := s.getGame(client); err != nil {
+		s.disconnect(client)
+	}
 
 	return err
 }
+
+// disconnect ends the client's session, revoking its token.
+func (s *Server) disconnect(client *network.Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[client.SessionID]
+
+	if !ok || sess.client != client {
+		return
+	}
+
+	s.issuer.Revoke(sess.claims)
+	delete(s.sessions, client.SessionID)
+	s.usernamePool.Delete(username.Key(client.Username))
+
+	log.Logger.Info("Disconnected client", "client", client)
+}
+
+// cleanupSessions disconnects the clients whose token expired by now, who
+// aren't streaming or playing a game, then forgets the revoked tokens that
+// expired.
+func (s *Server) cleanupSessions(now time.Time) {
+	s.mu.Lock()
+
+	for id, sess := range s.sessions {
+		if now.Before(sess.claims.ExpiresAt) || sess.client.Stream() != nil {
+			continue
+		}
+
+		// The game holds on to the player's name until it's over
+		if g, err := s.getGame(sess.client); err == nil && !g.Manager.IsOver() {
+			continue
+		}
+
+		delete(s.sessions, id)
+		s.usernamePool.Delete(username.Key(sess.client.Username))
+
+		log.Logger.Info("Disconnected client, token expired", "client", sess.client)
+	}
+
+	s.mu.Unlock()
+
+	s.issuer.Cleanup()
+}
+
+// runSessionCleanup calls cleanupSessions every interval until stop is
+// closed.
+func (s *Server) runSessionCleanup(interval time.Duration, stop <-chan struct{}) {
+	ticker := s.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C():
+			s.cleanupSessions(now)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// logQueueStats logs the outbound queue of every client with events still
+// waiting to be sent, or that has had events dropped.
+func (s *Server) logQueueStats() {
+	for _, g := range s.lobby.ListGames() {
+		for name, stats := range g.Manager.QueueStats() {
+			if stats.Depth == 0 && stats.Dropped == 0 {
+				continue
+			}
+
+			log.Logger.Info(
+				"Client queue", "game", g.ID, "client", name,
+				"depth", stats.Depth, "maxDepth", stats.MaxDepth,
+				"sent", stats.Sent, "dropped", stats.Dropped,
+			)
+		}
+	}
+}
+
+// runQueueStatsLog calls logQueueStats every interval until stop is closed.
+func (s *Server) runQueueStatsLog(interval time.Duration, stop <-chan struct{}) {
+	ticker := s.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			s.logQueueStats()
+		case <-stop:
+			return
+		}
+	}
+}
@@ -1,12 +1,28 @@
 package server
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/maria-mz/bash-battle-proto/proto"
+	"github.com/maria-mz/bash-battle-server/auth"
+	"github.com/maria-mz/bash-battle-server/clock"
 	"github.com/maria-mz/bash-battle-server/config"
+	"github.com/maria-mz/bash-battle-server/game"
+	"github.com/maria-mz/bash-battle-server/game/gametest"
 	"github.com/maria-mz/bash-battle-server/log"
+	"github.com/maria-mz/bash-battle-server/server/game_manager"
+	"github.com/maria-mz/bash-battle-server/server/lobby"
+	"github.com/maria-mz/bash-battle-server/server/network"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	protobuf "google.golang.org/protobuf/proto"
 )
 
 var testConfig = config.Config{
@@ -18,11 +34,27 @@ var testConfig = config.Config{
 	},
 }
 
+var testCatalog = game.NewCatalog(
+	game.Challenge{ID: "challenge-1", Question: "question-1"},
+	game.Challenge{ID: "challenge-2", Question: "question-2"},
+)
+
 func TestMain(m *testing.M) {
 	log.InitLogger()
 	m.Run()
 }
 
+// connect connects a client, as the router would once authenticated.
+func connect(t *testing.T, server *Server, username string) *network.Client {
+	resp, err := server.Connect(&proto.ConnectRequest{Username: username})
+	assert.Nil(t, err)
+
+	client, err := server.Authenticate(resp.Token)
+	assert.Nil(t, err)
+
+	return client
+}
+
 type connectTest struct {
 	name       string
 	requests   []*proto.ConnectRequest
@@ -30,7 +62,7 @@ type connectTest struct {
 }
 
 func (test connectTest) run(t *testing.T) {
-	server := NewServer(testConfig)
+	server, _ := NewServer(testConfig, testCatalog, gametest.Verifier(t))
 
 	for i := 0; i < len(test.requests)-1; i++ {
 		server.Connect(test.requests[i])
@@ -47,7 +79,8 @@ func (test connectTest) run(t *testing.T) {
 		assert.NotNil(t, resp)
 		assert.NotEqual(t, resp.Token, "")
 		assert.Nil(t, err)
-		_, ok := server.clients[resp.Token]
+		claims, _ := server.issuer.Verify(resp.Token)
+		_, ok := server.sessions[claims.SessionID]
 		assert.True(t, ok)
 	}
 }
@@ -77,6 +110,28 @@ var connectTests = []connectTest{
 		},
 		shouldFail: true,
 	},
+	{
+		name: "look-alike name taken",
+		requests: []*proto.ConnectRequest{
+			{Username: "player-1"},
+			{Username: "PLAYER_l"},
+		},
+		shouldFail: true,
+	},
+	{
+		name: "invalid name",
+		requests: []*proto.ConnectRequest{
+			{Username: "\tplayer\x00"},
+		},
+		shouldFail: true,
+	},
+	{
+		name: "reserved name",
+		requests: []*proto.ConnectRequest{
+			{Username: "Admin"},
+		},
+		shouldFail: true,
+	},
 	{
 		name: "more than max players",
 		requests: []*proto.ConnectRequest{
@@ -96,3 +151,445 @@ func TestConnect(t *testing.T) {
 		})
 	}
 }
+
+func TestJoinGame(t *testing.T) {
+	server, _ := NewServer(testConfig, testCatalog, gametest.Verifier(t))
+	defer server.Shutdown()
+
+	client1 := connect(t, server, "player-1")
+	client2 := connect(t, server, "player-2")
+
+	_, err := server.GetPlayers(client1)
+	assert.ErrorIs(t, err, ErrNotInGame)
+
+	info, err := server.CreateGame(client1, &proto.GameConfig{MaxPlayers: protobuf.Int32(2)})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), info.Config.GetMaxPlayers())
+	assert.Equal(t, int32(testConfig.GameConfig.Rounds), info.Config.GetRounds())
+
+	assert.Nil(t, server.JoinGame(client1, info.GameId))
+	assert.ErrorIs(t, server.JoinGame(client1, info.GameId), ErrAlreadyInGame)
+	assert.Nil(t, server.JoinGame(client2, ""))
+
+	players, err := server.GetPlayers(client2)
+	assert.Nil(t, err)
+	assert.Len(t, players.Players, 2)
+
+	games, err := server.ListGames(client1)
+	assert.Nil(t, err)
+	assert.Len(t, games.Games, 1)
+	assert.Equal(t, int32(2), games.Games[0].NumPlayers)
+}
+
+func TestJoinGame_AfterGameOver(t *testing.T) {
+	server, _ := NewServer(testConfig, testCatalog, gametest.Verifier(t))
+	defer server.Shutdown()
+
+	client := connect(t, server, "player-1")
+
+	info, err := server.CreateGame(client, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, server.JoinGame(client, info.GameId))
+
+	oldGame, _ := server.lobby.GetGame(info.GameId)
+	oldGame.Manager.Terminate()
+
+	assert.Nil(t, server.JoinGame(client, ""))
+	assert.NotEqual(t, info.GameId, client.GameID())
+	assert.False(t, oldGame.Manager.HasPlayer(client.Username))
+
+	newGame, _ := server.lobby.GetGame(client.GameID())
+	stream := newTestStream()
+	done := make(chan error, 1)
+
+	go func() {
+		done <- server.Stream(client, stream) // Blocks until closed
+	}()
+
+	assert.Eventually(t, func() bool {
+		return newGame.Manager.NumActiveClients() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// Cleaning up the old game must leave the new game's stream alone
+	removed := server.lobby.Cleanup(time.Now().Add(time.Hour))
+	assert.Equal(t, []string{info.GameId}, removed)
+
+	assert.Never(t, func() bool {
+		return len(done) > 0
+	}, 100*time.Millisecond, 10*time.Millisecond)
+
+	close(stream.closed)
+	assert.NotErrorIs(t, <-done, game_manager.ErrStreamOnGameOver)
+}
+
+func TestStream_GameOver(t *testing.T) {
+	server, _ := NewServer(testConfig, testCatalog, gametest.Verifier(t))
+	defer server.Shutdown()
+
+	client := connect(t, server, "player-1")
+
+	info, err := server.CreateGame(client, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, server.JoinGame(client, info.GameId))
+
+	g, _ := server.lobby.GetGame(info.GameId)
+	g.Manager.Terminate()
+
+	// Let the game's goroutines wind down first
+	before := runtime.NumGoroutine()
+
+	assert.Eventually(t, func() bool {
+		last := before
+		before = runtime.NumGoroutine()
+		return before == last
+	}, time.Second, 50*time.Millisecond)
+
+	err = server.Stream(client, newTestStream())
+	assert.ErrorIs(t, err, game_manager.ErrStreamOnGameOver)
+	assert.Nil(t, client.Stream())
+
+	// The stream never got going, its sender must still stop
+	assert.Eventually(t, func() bool {
+		return runtime.NumGoroutine() <= before
+	}, time.Second, 10*time.Millisecond)
+}
+
+// testStream is the stream of a simulated client. Events sent on it are
+// dropped, and it ends once closed.
+type testStream struct {
+	grpc.ServerStream
+	closed chan struct{}
+}
+
+func newTestStream() *testStream {
+	return &testStream{closed: make(chan struct{})}
+}
+
+func (s *testStream) Send(*proto.Event) error {
+	return nil
+}
+
+func (s *testStream) Recv() (*proto.AckMsg, error) {
+	<-s.closed
+	return nil, io.EOF
+}
+
+func TestConnect_Concurrent(t *testing.T) {
+	server, _ := NewServer(testConfig, testCatalog, gametest.Verifier(t))
+	defer server.Shutdown()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	connected := 0
+
+	// Every name is requested twice at the same time, only one can have it
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := server.Connect(&proto.ConnectRequest{Username: fmt.Sprintf("player-%d", i/2)})
+
+			if err == nil {
+				mu.Lock()
+				connected++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 50, connected)
+	assert.Len(t, server.sessions, 50)
+}
+
+func TestManyClients_Concurrent(t *testing.T) {
+	const numClients = 60
+
+	server, _ := NewServer(testConfig, testCatalog, gametest.Verifier(t))
+	defer server.Shutdown()
+
+	clients := make([]*network.Client, numClients)
+	streams := make([]*testStream, numClients)
+
+	var joined sync.WaitGroup
+	var streaming sync.WaitGroup
+
+	for i := 0; i < numClients; i++ {
+		joined.Add(1)
+		streaming.Add(1)
+
+		go func() {
+			defer streaming.Done()
+
+			client := connect(t, server, fmt.Sprintf("player-%d", i))
+
+			assert.Nil(t, server.JoinGame(client, ""))
+
+			_, err := server.GetPlayers(client)
+			assert.Nil(t, err)
+
+			_, err = server.ListGames(client)
+			assert.Nil(t, err)
+
+			clients[i] = client
+			streams[i] = newTestStream()
+			joined.Done()
+
+			server.Stream(client, streams[i]) // Blocks until closed
+		}()
+	}
+
+	joined.Wait()
+
+	games, _ := server.ListGames(clients[0])
+	numPlayers := 0
+
+	for _, g := range games.Games {
+		assert.LessOrEqual(t, g.NumPlayers, int32(testConfig.GameConfig.MaxPlayers))
+		numPlayers += int(g.NumPlayers)
+	}
+
+	assert.Equal(t, numClients, numPlayers)
+
+	for _, stream := range streams {
+		close(stream.closed)
+	}
+
+	streaming.Wait()
+}
+
+func TestNewServer_InvalidConfig(t *testing.T) {
+	conf := testConfig
+	conf.Stream.OnOverflow = "block"
+	conf.GameConfig.Scoring = "golf"
+	conf.GameConfig.Rounds = -1
+
+	server, err := NewServer(conf, testCatalog, gametest.Verifier(t))
+
+	assert.Nil(t, server)
+	assert.ErrorIs(t, err, config.ErrInvalidConfig)
+	assert.ErrorContains(t, err, "stream.onOverflow")
+	assert.ErrorContains(t, err, "gameConfig.scoring")
+	assert.ErrorContains(t, err, "gameConfig.rounds")
+}
+
+func TestCreateGame_InvalidConfig(t *testing.T) {
+	server, _ := NewServer(testConfig, testCatalog, gametest.Verifier(t))
+	client := connect(t, server, "player")
+
+	info, err := server.CreateGame(client, &proto.GameConfig{MaxPlayers: protobuf.Int32(1000), Difficulty: proto.Difficulty(7).Enum()})
+
+	assert.Nil(t, info)
+	assert.ErrorIs(t, err, config.ErrInvalidConfig)
+	assert.ErrorContains(t, err, "maxPlayers")
+	assert.ErrorContains(t, err, "difficulty")
+}
+
+func TestReload(t *testing.T) {
+	server, _ := NewServer(testConfig, testCatalog, gametest.Verifier(t))
+	defer server.Shutdown()
+
+	client1 := connect(t, server, "player-1")
+	client2 := connect(t, server, "player-2")
+
+	assert.Nil(t, server.JoinGame(client1, ""))
+
+	conf := testConfig
+	conf.Port = 9999
+	conf.Stream.QueueSize = 8
+	conf.GameConfig.Rounds = 1
+	conf.GameConfig.MaxPlayers = 5
+
+	assert.Nil(t, server.Reload(conf))
+
+	// Not a runtime setting
+	assert.Equal(t, uint16(config.DefaultPort), server.Config().Port)
+	assert.Equal(t, 8, server.Config().Stream.QueueSize)
+
+	// The game in progress keeps its config
+	old, err := server.GetGameConfig(client1)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(testConfig.GameConfig.Rounds), old.GetRounds())
+
+	// New games use the new one
+	info, err := server.CreateGame(client2, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), info.Config.GetRounds())
+	assert.Equal(t, int32(5), info.Config.GetMaxPlayers())
+
+	conf.GameConfig.Rounds = 0
+	conf.GameConfig.RoundDuration = -1
+	assert.ErrorIs(t, server.Reload(conf), config.ErrInvalidConfig)
+	assert.Equal(t, 1, server.Config().GameConfig.Rounds)
+}
+
+func TestRefreshToken(t *testing.T) {
+	server, _ := NewServer(testConfig, testCatalog, gametest.Verifier(t))
+	defer server.Shutdown()
+
+	resp, _ := server.Connect(&proto.ConnectRequest{Username: "player-1"})
+	client, _ := server.Authenticate(resp.Token)
+
+	assert.Nil(t, server.JoinGame(client, ""))
+
+	refreshed, err := server.RefreshToken(client, resp.Token)
+	assert.Nil(t, err)
+
+	// The old token is revoked, the new one knows the game
+	_, err = server.Authenticate(resp.Token)
+	assert.ErrorIs(t, err, auth.ErrTokenRevoked)
+
+	claims, err := server.issuer.Verify(refreshed.Token)
+	assert.Nil(t, err)
+	assert.Equal(t, client.GameID(), claims.GameID)
+
+	same, err := server.Authenticate(refreshed.Token)
+	assert.Nil(t, err)
+	assert.Same(t, client, same)
+}
+
+func TestLogout(t *testing.T) {
+	server, _ := NewServer(testConfig, testCatalog, gametest.Verifier(t))
+	defer server.Shutdown()
+
+	resp, _ := server.Connect(&proto.ConnectRequest{Username: "player-1"})
+	client, _ := server.Authenticate(resp.Token)
+
+	assert.Nil(t, server.Logout(client))
+
+	_, err := server.Authenticate(resp.Token)
+	assert.ErrorIs(t, err, auth.ErrTokenRevoked)
+
+	// The name is free again
+	connect(t, server, "player-1")
+}
+
+func TestAuthenticate_RestoresSessionAfterRestart(t *testing.T) {
+	conf := testConfig
+	conf.Auth.Secret = "a-secret-at-least-32-bytes-long!"
+	conf.Auth.RevokedFile = filepath.Join(t.TempDir(), "revoked.json")
+
+	server, _ := NewServer(conf, testCatalog, gametest.Verifier(t))
+	resp, _ := server.Connect(&proto.ConnectRequest{Username: "player-1"})
+	server.Shutdown()
+
+	restarted, _ := NewServer(conf, testCatalog, gametest.Verifier(t))
+	defer restarted.Shutdown()
+
+	client, err := restarted.Authenticate(resp.Token)
+	assert.Nil(t, err)
+	assert.Equal(t, "player-1", client.Username)
+
+	_, err = restarted.Connect(&proto.ConnectRequest{Username: "player-1"})
+	assert.ErrorIs(t, err, ErrUsernameTaken)
+
+	// Without the same secret, the token means nothing
+	other, _ := NewServer(testConfig, testCatalog, gametest.Verifier(t))
+	defer other.Shutdown()
+
+	_, err = other.Authenticate(resp.Token)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+}
+
+func TestAuthenticate_RevokedBeforeRestart(t *testing.T) {
+	conf := testConfig
+	conf.Auth.Secret = "a-secret-at-least-32-bytes-long!"
+	conf.Auth.RevokedFile = filepath.Join(t.TempDir(), "revoked.json")
+
+	server, _ := NewServer(conf, testCatalog, gametest.Verifier(t))
+	resp, _ := server.Connect(&proto.ConnectRequest{Username: "player-1"})
+	client, _ := server.Authenticate(resp.Token)
+	assert.Nil(t, server.Logout(client))
+	server.Shutdown()
+
+	restarted, err := NewServer(conf, testCatalog, gametest.Verifier(t))
+	assert.Nil(t, err)
+	defer restarted.Shutdown()
+
+	_, err = restarted.Authenticate(resp.Token)
+	assert.ErrorIs(t, err, auth.ErrTokenRevoked)
+
+	// Refusing to start beats forgetting every revocation
+	assert.Nil(t, os.WriteFile(conf.Auth.RevokedFile, []byte("not json"), 0o600))
+
+	_, err = NewServer(conf, testCatalog, gametest.Verifier(t))
+	assert.NotNil(t, err)
+}
+
+func TestRunSessionCleanup(t *testing.T) {
+	clk := clock.NewFake(time.Now())
+	server, _ := newServer(testConfig, testCatalog, gametest.Verifier(t), clk)
+	defer server.Shutdown()
+
+	connect(t, server, "player-1")
+
+	clk.BlockUntil(3) // The lobby cleanup, session cleanup and queue stats tickers
+	clk.Advance(config.DefaultTokenTTL * time.Second)
+
+	// Ticks are dropped while a cleanup runs, so keep ticking until one
+	// past the token's expiry gets through
+	assert.Eventually(t, func() bool {
+		clk.Advance(SessionCleanupInterval)
+
+		server.mu.Lock()
+		defer server.mu.Unlock()
+
+		return len(server.sessions) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestRunLobbyCleanup(t *testing.T) {
+	clk := clock.NewFake(time.Now())
+	server, _ := newServer(testConfig, testCatalog, gametest.Verifier(t), clk)
+	defer server.Shutdown()
+
+	client := connect(t, server, "player-1")
+
+	info, err := server.CreateGame(client, nil)
+	assert.Nil(t, err)
+
+	g, _ := server.lobby.GetGame(info.GameId)
+	g.Manager.Terminate()
+
+	clk.BlockUntil(3)
+	clk.Advance(lobby.FinishedGameTTL)
+
+	// Games are timed by the server's clock, so the finished game is
+	// removed once it has been over for long enough
+	assert.Eventually(t, func() bool {
+		clk.Advance(lobby.CleanupInterval)
+
+		_, ok := server.lobby.GetGame(info.GameId)
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCleanupSessions(t *testing.T) {
+	server, _ := NewServer(testConfig, testCatalog, gametest.Verifier(t))
+	defer server.Shutdown()
+
+	clk := clock.NewFake(time.Now())
+	server.issuer = auth.NewIssuer([]byte("test-key"), time.Hour, clk)
+
+	resp, _ := server.Connect(&proto.ConnectRequest{Username: "player-1"})
+	idle, _ := server.Authenticate(resp.Token)
+	playing := connect(t, server, "player-2")
+
+	assert.Nil(t, server.JoinGame(playing, ""))
+
+	clk.Advance(30 * time.Minute)
+	server.cleanupSessions(clk.Now())
+	assert.Len(t, server.sessions, 2)
+
+	clk.Advance(30 * time.Minute)
+	server.cleanupSessions(clk.Now())
+
+	// Players keep their session until their game is over
+	assert.Len(t, server.sessions, 1)
+	assert.Contains(t, server.sessions, playing.SessionID)
+	assert.NotContains(t, server.sessions, idle.SessionID)
+	assert.False(t, server.usernamePool.Contains("player-1"))
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/maria-mz/bash-battle-proto/proto"
 	"github.com/maria-mz/bash-battle-server/config"
+	"github.com/maria-mz/bash-battle-server/game"
+	"github.com/maria-mz/bash-battle-server/log"
 	"github.com/maria-mz/bash-battle-server/router"
 	"github.com/maria-mz/bash-battle-server/server"
 	"google.golang.org/grpc"
@@ -15,21 +17,49 @@ import (
 type Service struct {
 	config          config.Config
 	listener        net.Listener
+	server          *server.Server
 	serverRegistrar *grpc.Server
+	certs           *certStore // nil without TLS
 }
 
-func NewService(conf config.Config) *Service {
+// NewService creates the gRPC service for the config. Every game checks
+// submissions with verifier.
+func NewService(conf config.Config, catalog *game.Catalog, verifier *game.Verifier) (*Service, error) {
 	s := &Service{}
 	s.config = conf
 
-	server := server.NewServer(s.config)
-	router := router.NewServerRouter(server)
+	var opts []grpc.ServerOption
 
-	s.serverRegistrar = grpc.NewServer()
+	if conf.TLS.Enabled {
+		certs, err := newCertStore(conf.TLS, []string{conf.Host, "localhost", "127.0.0.1", "::1"})
 
-	proto.RegisterBashBattleServer(s.serverRegistrar, router)
+		if err != nil {
+			return nil, fmt.Errorf("tls: %w", err)
+		}
 
-	return s
+		s.certs = certs
+		opts = append(opts, grpc.Creds(certs.credentials()))
+	}
+
+	server, err := server.NewServer(s.config, catalog, verifier)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.server = server
+	serverRouter := router.NewServerRouter(server)
+
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(router.UnaryErrorInterceptor(), router.UnaryAuthInterceptor(server)),
+		grpc.ChainStreamInterceptor(router.StreamErrorInterceptor(), router.StreamAuthInterceptor(server)),
+	)
+
+	s.serverRegistrar = grpc.NewServer(opts...)
+
+	proto.RegisterBashBattleServer(s.serverRegistrar, serverRouter)
+
+	return s, nil
 }
 
 func (s *Service) Run() error {
@@ -43,12 +73,37 @@ func (s *Service) Run() error {
 		return err
 	}
 
+	return s.Serve(lis) // blocking
+}
+
+// Serve serves the service on lis, such as an in-memory listener in tests.
+func (s *Service) Serve(lis net.Listener) error {
+	if s.listener != nil {
+		return errors.New("server is already running")
+	}
+
 	s.listener = lis
 
-	err = s.serverRegistrar.Serve(s.listener) // blocking
+	err := s.serverRegistrar.Serve(s.listener) // blocking
 	return err
 }
 
+// Reload applies a new config to the running service, see Server.Reload.
+// The log level changes and certificate files are reloaded right away.
+func (s *Service) Reload(conf config.Config) error {
+	if err := s.server.Reload(conf); err != nil {
+		return err
+	}
+
+	if s.certs != nil {
+		if err := s.certs.Reload(); err != nil {
+			log.Logger.Error("Failed to reload certificates, keeping the current ones", "err", err)
+		}
+	}
+
+	return log.SetLevel(s.server.Config().LogLevel)
+}
+
 func (s *Service) Shutdown() {
 	// TODO: reverse order, see how ongoing streams are handled. do they hang?
 	if s.serverRegistrar != nil {
@@ -57,4 +112,7 @@ func (s *Service) Shutdown() {
 	if s.listener != nil {
 		s.listener.Close()
 	}
+	if s.server != nil {
+		s.server.Shutdown()
+	}
 }
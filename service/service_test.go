@@ -0,0 +1,593 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/maria-mz/bash-battle-proto/proto"
+	"github.com/maria-mz/bash-battle-server/config"
+	"github.com/maria-mz/bash-battle-server/game/gametest"
+	"github.com/maria-mz/bash-battle-server/log"
+	"github.com/maria-mz/bash-battle-server/server/game_manager"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	solution = "sort input.txt"
+	wrong    = "cat input.txt"
+
+	gameTimeout = 30 * time.Second
+)
+
+var testConfig = config.Config{
+	GameConfig: config.GameConfig{
+		MaxPlayers:         2,
+		Rounds:             2,
+		RoundDuration:      2,
+		CountdownDuration:  1,
+		AckTimeout:         1,
+		SubmissionDuration: 1,
+	},
+}
+
+func TestMain(m *testing.M) {
+	log.InitLogger()
+	m.Run()
+}
+
+// harness runs the service in-process, over an in-memory connection.
+type harness struct {
+	t       *testing.T
+	lis     *bufconn.Listener
+	service *Service
+
+	// Used by clients to connect, insecure by default
+	creds credentials.TransportCredentials
+}
+
+func newHarness(t *testing.T) *harness {
+	return newHarnessWithConfig(t, testConfig)
+}
+
+func newHarnessWithConfig(t *testing.T, conf config.Config) *harness {
+	service, err := NewService(conf, gametest.SortCatalog(t), gametest.Verifier(t))
+
+	assert.Nil(t, err)
+
+	lis := bufconn.Listen(1 << 20)
+
+	go service.Serve(lis)
+	t.Cleanup(service.Shutdown)
+
+	return &harness{t: t, lis: lis, service: service, creds: insecure.NewCredentials()}
+}
+
+// newBot connects a bot that submits commands[round] once each round
+// starts, if it has a command for the round.
+func (h *harness) newBot(username string, commands map[int]string) *bot {
+	client := h.dial()
+
+	res, err := client.Connect(context.Background(), &proto.ConnectRequest{Username: username})
+
+	assert.Nil(h.t, err)
+
+	return &bot{
+		t:        h.t,
+		username: username,
+		client:   client,
+		ctx:      metadata.AppendToOutgoingContext(context.Background(), "authorization", res.GetToken()),
+		commands: commands,
+	}
+}
+
+func (h *harness) dial() proto.BashBattleClient {
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return h.lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(h.creds),
+	)
+
+	assert.Nil(h.t, err)
+	h.t.Cleanup(func() { conn.Close() })
+
+	return proto.NewBashBattleClient(conn)
+}
+
+// bot is a scripted player. It records every event it receives, and acks
+// and submits the way a real client would.
+type bot struct {
+	t        *testing.T
+	username string
+	client   proto.BashBattleClient
+	ctx      context.Context
+	commands map[int]string
+
+	// Submitted once asked for the round's score, instead of during it
+	finals map[int]string
+	round  int
+
+	// Called with each event the bot records, while streaming
+	onEvent func(event string)
+
+	events []string
+	mu     sync.Mutex
+}
+
+func (b *bot) createGame() string {
+	info, err := b.client.CreateGame(b.ctx, &proto.GameConfig{})
+
+	assert.Nil(b.t, err)
+
+	return info.GetGameId()
+}
+
+func (b *bot) joinGame(gameID string) error {
+	_, err := b.client.JoinGame(b.ctx, &proto.JoinGameRequest{GameId: gameID})
+	return err
+}
+
+// stream streams the game in the background, until the game is over or
+// leave is called. done is closed once the stream has ended.
+func (b *bot) stream() (leave func(), done <-chan struct{}) {
+	ctx, cancel := context.WithCancel(b.ctx)
+	b.t.Cleanup(cancel)
+
+	stream, err := b.client.Stream(ctx)
+
+	assert.Nil(b.t, err)
+
+	ch := make(chan struct{})
+
+	go func() {
+		defer close(ch)
+		defer cancel()
+
+		for {
+			event, err := stream.Recv()
+
+			if err != nil {
+				return
+			}
+
+			if over := b.handle(stream, event); over {
+				return
+			}
+		}
+	}()
+
+	return cancel, ch
+}
+
+// handle records the event and responds to it. Returns true once the game
+// is over.
+func (b *bot) handle(stream proto.BashBattle_StreamClient, event *proto.Event) bool {
+	switch e := event.GetEvent().(type) {
+
+	case *proto.Event_PlayerJoined:
+		b.record("PlayerJoined " + e.PlayerJoined.GetPlayer().GetUsername())
+
+	case *proto.Event_PlayerLeft:
+		b.record("PlayerLeft " + e.PlayerLeft.GetPlayer().GetUsername())
+
+	case *proto.Event_LoadRound:
+		b.record(fmt.Sprintf("LoadRound %d", e.LoadRound.GetRoundNumber()))
+		stream.Send(&proto.AckMsg{Ack: &proto.AckMsg_RoundLoaded{RoundLoaded: &proto.RoundLoaded{}}})
+
+	case *proto.Event_CountingDown:
+		b.record(fmt.Sprintf("CountingDown %d", e.CountingDown.GetRoundNumber()))
+
+	case *proto.Event_RoundStarted:
+		b.round = int(e.RoundStarted.GetRoundNumber())
+		b.record(fmt.Sprintf("RoundStarted %d", b.round))
+
+		if command, ok := b.commands[b.round]; ok {
+			b.submit(stream, command)
+		}
+
+	case *proto.Event_SubmissionResult:
+		result := e.SubmissionResult
+		b.record(fmt.Sprintf("SubmissionResult accepted=%t won=%t", result.GetAccepted(), result.GetWon()))
+
+	case *proto.Event_SubmitRoundScore:
+		b.record("SubmitRoundScore")
+
+		if command, ok := b.finals[b.round]; ok {
+			b.submit(stream, command)
+		}
+
+	case *proto.Event_Leaderboard:
+		b.record(fmt.Sprintf("Leaderboard %d", e.Leaderboard.GetRoundNumber()))
+
+	case *proto.Event_GameOver:
+		b.record("GameOver " + strings.Join(e.GameOver.GetWinners(), ","))
+		return true
+	}
+
+	return false
+}
+
+func (b *bot) submit(stream proto.BashBattle_StreamClient, command string) {
+	stream.Send(&proto.AckMsg{Ack: &proto.AckMsg_RoundSubmission{
+		RoundSubmission: &proto.RoundSubmission{RoundStats: &proto.RoundStats{Command: command}},
+	}})
+}
+
+// record adds an event, unless it repeats the last one: events are resent
+// until acked, and a snapshot can overlap with a broadcast.
+func (b *bot) record(event string) {
+	b.mu.Lock()
+
+	if n := len(b.events); n == 0 || b.events[n-1] != event {
+		b.events = append(b.events, event)
+	}
+
+	onEvent := b.onEvent
+	b.mu.Unlock()
+
+	if onEvent != nil {
+		onEvent(event)
+	}
+}
+
+func (b *bot) recorded() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]string(nil), b.events...)
+}
+
+func waitFor(t *testing.T, done <-chan struct{}) {
+	select {
+	case <-done:
+	case <-time.After(gameTimeout):
+		t.Fatal("timed out waiting for the game")
+	}
+}
+
+func TestEndToEnd_FullGame(t *testing.T) {
+	h := newHarness(t)
+
+	alice := h.newBot("alice", map[int]string{1: solution, 2: solution})
+	bob := h.newBot("bob", map[int]string{1: wrong, 2: solution})
+
+	gameID := alice.createGame()
+
+	assert.Nil(t, alice.joinGame(gameID))
+	assert.Nil(t, bob.joinGame(gameID)) // Game is full, it starts
+
+	_, aliceDone := alice.stream()
+	_, bobDone := bob.stream()
+
+	waitFor(t, aliceDone)
+	waitFor(t, bobDone)
+
+	// Round 2 is over as soon as both solved it
+	assert.Equal(t, []string{
+		"LoadRound 1",
+		"CountingDown 1",
+		"RoundStarted 1",
+		"SubmissionResult accepted=true won=true",
+		"SubmitRoundScore",
+		"Leaderboard 1",
+		"LoadRound 2",
+		"CountingDown 2",
+		"RoundStarted 2",
+		"SubmissionResult accepted=true won=true",
+		"SubmitRoundScore",
+		"GameOver alice",
+	}, alice.recorded())
+
+	assert.Equal(t, []string{
+		"LoadRound 1",
+		"CountingDown 1",
+		"RoundStarted 1",
+		"SubmissionResult accepted=true won=false",
+		"SubmitRoundScore",
+		"Leaderboard 1",
+		"LoadRound 2",
+		"CountingDown 2",
+		"RoundStarted 2",
+		"SubmissionResult accepted=true won=true",
+		"SubmitRoundScore",
+		"GameOver alice",
+	}, bob.recorded())
+}
+
+func TestEndToEnd_FinalSubmissions(t *testing.T) {
+	// The window closes as soon as both submitted, a long one only keeps a
+	// slow machine from missing it
+	conf := testConfig
+	conf.GameConfig.SubmissionDuration = 30
+
+	h := newHarnessWithConfig(t, conf)
+
+	// Both only submit once the round is over. Each sees its result before
+	// the round is scored, whichever submission is verified first
+	alice := h.newBot("alice", nil)
+	alice.finals = map[int]string{1: solution, 2: solution}
+
+	bob := h.newBot("bob", nil)
+	bob.finals = map[int]string{1: wrong, 2: wrong}
+
+	gameID := alice.createGame()
+
+	assert.Nil(t, alice.joinGame(gameID))
+	assert.Nil(t, bob.joinGame(gameID))
+
+	_, aliceDone := alice.stream()
+	_, bobDone := bob.stream()
+
+	waitFor(t, aliceDone)
+	waitFor(t, bobDone)
+
+	assert.Equal(t, []string{
+		"LoadRound 1",
+		"CountingDown 1",
+		"RoundStarted 1",
+		"SubmitRoundScore",
+		"SubmissionResult accepted=true won=true",
+		"Leaderboard 1",
+		"LoadRound 2",
+		"CountingDown 2",
+		"RoundStarted 2",
+		"SubmitRoundScore",
+		"SubmissionResult accepted=true won=true",
+		"GameOver alice",
+	}, alice.recorded())
+
+	assert.Equal(t, []string{
+		"LoadRound 1",
+		"CountingDown 1",
+		"RoundStarted 1",
+		"SubmitRoundScore",
+		"SubmissionResult accepted=true won=false",
+		"Leaderboard 1",
+		"LoadRound 2",
+		"CountingDown 2",
+		"RoundStarted 2",
+		"SubmitRoundScore",
+		"SubmissionResult accepted=true won=false",
+		"GameOver alice",
+	}, bob.recorded())
+}
+
+func TestEndToEnd_DisconnectAndLateJoiners(t *testing.T) {
+	h := newHarness(t)
+
+	alice := h.newBot("alice", map[int]string{2: wrong})
+	bob := h.newBot("bob", map[int]string{2: solution})
+	carol := h.newBot("carol", nil)
+
+	gameID := alice.createGame()
+
+	assert.Nil(t, alice.joinGame(gameID))
+	assert.Nil(t, bob.joinGame(gameID))
+
+	// Too late to join a game that started
+	err := carol.joinGame(gameID)
+	assert.ErrorContains(t, err, game_manager.ErrJoinOnGameStarted.Error())
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// bob drops once round 1 starts, and comes back once round 2 starts
+	bobLeave := make(chan func(), 1)
+	bobBack := make(chan struct{})
+
+	bob.onEvent = func(event string) {
+		if event == "RoundStarted 1" {
+			(<-bobLeave)()
+		}
+	}
+
+	alice.onEvent = func(event string) {
+		if event == "RoundStarted 2" {
+			close(bobBack)
+		}
+	}
+
+	_, aliceDone := alice.stream()
+	leave, bobDone := bob.stream()
+	bobLeave <- leave
+
+	waitFor(t, bobDone)
+	waitFor(t, bobBack)
+
+	bob.mu.Lock()
+	bob.onEvent = nil
+	bob.mu.Unlock()
+
+	_, bobDone = bob.stream()
+
+	waitFor(t, aliceDone)
+	waitFor(t, bobDone)
+
+	assert.Equal(t, []string{
+		"LoadRound 1",
+		"CountingDown 1",
+		"RoundStarted 1",
+		"PlayerLeft bob",
+		"SubmitRoundScore",
+		"Leaderboard 1",
+		"LoadRound 2",
+		"CountingDown 2",
+		"RoundStarted 2",
+		"SubmissionResult accepted=true won=false",
+		"SubmitRoundScore",
+		"GameOver bob",
+	}, alice.recorded())
+
+	// Caught up with a snapshot when coming back
+	assert.Equal(t, []string{
+		"LoadRound 1",
+		"CountingDown 1",
+		"RoundStarted 1",
+		"Leaderboard 1",
+		"LoadRound 2",
+		"RoundStarted 2",
+		"SubmissionResult accepted=true won=true",
+		"SubmitRoundScore",
+		"GameOver bob",
+	}, bob.recorded())
+}
+
+// tlsConfig returns a config serving TLS from dir, with a self-signed
+// certificate.
+func tlsConfig(dir string) config.Config {
+	conf := testConfig
+	conf.TLS = config.TLSConfig{
+		Enabled:    true,
+		CertFile:   filepath.Join(dir, "server.crt"),
+		KeyFile:    filepath.Join(dir, "server.key"),
+		SelfSigned: true,
+	}
+	return conf
+}
+
+// clientTLS returns client credentials trusting the certificate in
+// caFile, presenting certs if given.
+func clientTLS(t *testing.T, caFile string, certs ...tls.Certificate) credentials.TransportCredentials {
+	data, err := os.ReadFile(caFile)
+	assert.Nil(t, err)
+
+	pool := x509.NewCertPool()
+	assert.True(t, pool.AppendCertsFromPEM(data))
+
+	return credentials.NewTLS(&tls.Config{RootCAs: pool, ServerName: "localhost", Certificates: certs})
+}
+
+func connect(client proto.BashBattleClient, username string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := client.Connect(ctx, &proto.ConnectRequest{Username: username})
+	return err
+}
+
+func TestTLS_SelfSigned(t *testing.T) {
+	conf := tlsConfig(t.TempDir())
+	h := newHarnessWithConfig(t, conf)
+
+	assert.NotNil(t, connect(h.dial(), "plaintext"))
+
+	h.creds = clientTLS(t, conf.TLS.CertFile)
+	assert.Nil(t, connect(h.dial(), "alice"))
+}
+
+func TestTLS_ClientCertificates(t *testing.T) {
+	dir := t.TempDir()
+
+	certPEM, keyPEM, err := generateSelfSigned(nil)
+	assert.Nil(t, err)
+
+	clientCert, err := tls.X509KeyPair(certPEM, keyPEM)
+	assert.Nil(t, err)
+
+	conf := tlsConfig(dir)
+	conf.TLS.ClientCAFile = filepath.Join(dir, "client-ca.crt")
+	conf.TLS.RequireClientCert = true
+	assert.Nil(t, os.WriteFile(conf.TLS.ClientCAFile, certPEM, 0o644))
+
+	h := newHarnessWithConfig(t, conf)
+
+	h.creds = clientTLS(t, conf.TLS.CertFile)
+	assert.NotNil(t, connect(h.dial(), "anonymous"))
+
+	otherPEM, otherKeyPEM, err := generateSelfSigned(nil)
+	assert.Nil(t, err)
+	otherCert, err := tls.X509KeyPair(otherPEM, otherKeyPEM)
+	assert.Nil(t, err)
+
+	h.creds = clientTLS(t, conf.TLS.CertFile, otherCert)
+	assert.NotNil(t, connect(h.dial(), "stranger"))
+
+	h.creds = clientTLS(t, conf.TLS.CertFile, clientCert)
+	assert.Nil(t, connect(h.dial(), "alice"))
+}
+
+func TestTLS_ReloadsCertificate(t *testing.T) {
+	conf := tlsConfig(t.TempDir())
+	h := newHarnessWithConfig(t, conf)
+
+	oldCreds := clientTLS(t, conf.TLS.CertFile)
+
+	certPEM, keyPEM, err := generateSelfSigned([]string{"localhost"})
+	assert.Nil(t, err)
+	assert.Nil(t, os.WriteFile(conf.TLS.KeyFile, keyPEM, 0o600))
+	assert.Nil(t, os.WriteFile(conf.TLS.CertFile, certPEM, 0o644))
+
+	// Files can be rewritten within the file system's time granularity
+	later := time.Now().Add(time.Minute)
+	assert.Nil(t, os.Chtimes(conf.TLS.CertFile, later, later))
+	assert.Nil(t, os.Chtimes(conf.TLS.KeyFile, later, later))
+
+	h.creds = oldCreds
+	assert.NotNil(t, connect(h.dial(), "alice"))
+
+	h.creds = clientTLS(t, conf.TLS.CertFile)
+	assert.Nil(t, connect(h.dial(), "alice"))
+}
+
+func TestAuth_Unauthenticated(t *testing.T) {
+	h := newHarness(t)
+	client := h.dial()
+
+	_, err := client.ListGames(context.Background(), &emptypb.Empty{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "not-a-token")
+
+	_, err = client.ListGames(ctx, &emptypb.Empty{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	stream, err := client.Stream(ctx)
+	assert.Nil(t, err)
+
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestAuth_RefreshAndLogout(t *testing.T) {
+	h := newHarness(t)
+	client := h.dial()
+
+	res, err := client.Connect(context.Background(), &proto.ConnectRequest{Username: "player"})
+	assert.Nil(t, err)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", res.GetToken())
+
+	refreshed, err := client.RefreshToken(ctx, &emptypb.Empty{})
+	assert.Nil(t, err)
+
+	_, err = client.ListGames(ctx, &emptypb.Empty{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "authorization", refreshed.GetToken())
+
+	_, err = client.ListGames(ctx, &emptypb.Empty{})
+	assert.Nil(t, err)
+
+	_, err = client.Logout(ctx, &emptypb.Empty{})
+	assert.Nil(t, err)
+
+	_, err = client.ListGames(ctx, &emptypb.Empty{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Nil(t, connect(client, "player"))
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/maria-mz/bash-battle-server/config"
+	"github.com/maria-mz/bash-battle-server/log"
+	"google.golang.org/grpc/credentials"
+)
+
+var ErrNoClientCAs = errors.New("no certificates found in client CA file")
+
+// selfSignedValidity is how long generated certificates are valid for.
+const selfSignedValidity = 365 * 24 * time.Hour
+
+// certStore holds the server certificate and the client CA pool, loaded
+// from the files of a TLSConfig. New handshakes pick up the files as soon
+// as they change; if they can't be loaded, the last good ones are kept.
+type certStore struct {
+	conf config.TLSConfig
+
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  map[string]time.Time // Of the files loaded
+	mu        sync.Mutex
+}
+
+func newCertStore(conf config.TLSConfig, hosts []string) (*certStore, error) {
+	if conf.SelfSigned {
+		if err := ensureSelfSigned(conf.CertFile, conf.KeyFile, hosts); err != nil {
+			return nil, err
+		}
+	}
+
+	store := &certStore{conf: conf}
+
+	if err := store.Reload(); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// Reload loads the certificate files again.
+func (store *certStore) Reload() error {
+	modTimes := store.readModTimes()
+
+	cert, err := tls.LoadX509KeyPair(store.conf.CertFile, store.conf.KeyFile)
+
+	if err != nil {
+		return err
+	}
+
+	var clientCAs *x509.CertPool
+
+	if store.conf.ClientCAFile != "" {
+		data, err := os.ReadFile(store.conf.ClientCAFile)
+
+		if err != nil {
+			return err
+		}
+
+		clientCAs = x509.NewCertPool()
+
+		if !clientCAs.AppendCertsFromPEM(data) {
+			return fmt.Errorf("%w: %s", ErrNoClientCAs, store.conf.ClientCAFile)
+		}
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.cert = &cert
+	store.clientCAs = clientCAs
+	store.modTimes = modTimes
+
+	return nil
+}
+
+func (store *certStore) files() []string {
+	files := []string{store.conf.CertFile, store.conf.KeyFile}
+
+	if store.conf.ClientCAFile != "" {
+		files = append(files, store.conf.ClientCAFile)
+	}
+
+	return files
+}
+
+func (store *certStore) readModTimes() map[string]time.Time {
+	modTimes := make(map[string]time.Time)
+
+	for _, file := range store.files() {
+		if info, err := os.Stat(file); err == nil {
+			modTimes[file] = info.ModTime()
+		}
+	}
+
+	return modTimes
+}
+
+// reloadIfChanged reloads the files if any of them changed since they were
+// last loaded.
+func (store *certStore) reloadIfChanged() {
+	modTimes := store.readModTimes()
+
+	store.mu.Lock()
+	changed := false
+
+	for file, modTime := range modTimes {
+		if !modTime.Equal(store.modTimes[file]) {
+			changed = true
+		}
+	}
+
+	store.mu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	if err := store.Reload(); err != nil {
+		log.Logger.Error("Failed to reload certificates, keeping the current ones", "err", err)
+		return
+	}
+
+	log.Logger.Info("Reloaded certificates")
+}
+
+// serverConfig is the TLS config of the server. Each handshake gets the
+// certificates loaded at the time.
+func (store *certStore) serverConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			store.reloadIfChanged()
+
+			store.mu.Lock()
+			defer store.mu.Unlock()
+
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				NextProtos:   []string{"h2"},
+				Certificates: []tls.Certificate{*store.cert},
+				ClientCAs:    store.clientCAs,
+				ClientAuth:   store.clientAuth(),
+			}, nil
+		},
+	}
+}
+
+func (store *certStore) clientAuth() tls.ClientAuthType {
+	switch {
+	case store.conf.RequireClientCert:
+		return tls.RequireAndVerifyClientCert
+	case store.clientCAs != nil:
+		return tls.VerifyClientCertIfGiven
+	default:
+		return tls.NoClientCert
+	}
+}
+
+func (store *certStore) credentials() credentials.TransportCredentials {
+	return credentials.NewTLS(store.serverConfig())
+}
+
+// ensureSelfSigned generates a self-signed certificate for hosts into
+// certFile and keyFile, unless certFile already exists.
+func ensureSelfSigned(certFile string, keyFile string, hosts []string) error {
+	if _, err := os.Stat(certFile); err == nil {
+		return nil
+	}
+
+	certPEM, keyPEM, err := generateSelfSigned(hosts)
+
+	if err != nil {
+		return err
+	}
+
+	for _, file := range []string{certFile, keyFile} {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return err
+		}
+	}
+
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(certFile, certPEM, 0o644); err != nil {
+		return err
+	}
+
+	log.Logger.Warn("Generated a self-signed certificate, for development only", "cert", certFile, "hosts", hosts)
+
+	return nil
+}
+
+// generateSelfSigned returns a PEM encoded certificate and key for hosts
+// (names or IPs). The certificate is its own CA, so clients can trust it
+// directly, and can be used by servers and clients alike.
+func generateSelfSigned(hosts []string) (certPEM []byte, keyPEM []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"Bash Battle"}, CommonName: "bash-battle-server"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else if host != "" {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	return certPEM, keyPEM, nil
+}
@@ -0,0 +1,166 @@
+// Package username decides which names players can connect with.
+//
+// Names are trimmed and NFC normalized, must be within the configured
+// length, and may only hold letters, digits, and the separators in
+// Separators. Two names that look alike share the same Key, and only one
+// of them can be connected at a time. Names whose key is reserved, or that
+// contain a blocklisted word, are rejected.
+package username
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/maria-mz/bash-battle-server/config"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+var ErrTooShort = errors.New("username is too short")
+var ErrTooLong = errors.New("username is too long")
+var ErrInvalidCharacters = errors.New("username has characters that aren't allowed")
+var ErrReserved = errors.New("username is reserved")
+var ErrBlocked = errors.New("username is not allowed")
+
+// Separators can be used in names, but not first. They are ignored when
+// names are compared, so "john_doe" and "johndoe" collide.
+const Separators = "_-."
+
+// reservedNames can't be taken by players, nor can names that look like
+// them.
+var reservedNames = []string{
+	"admin", "administrator", "bashbattle", "everyone", "mod", "moderator",
+	"official", "root", "server", "staff", "support", "system",
+}
+
+var reserved = make(map[string]bool)
+
+func init() {
+	for _, name := range reservedNames {
+		reserved[Key(name)] = true
+	}
+}
+
+// confusables maps characters to the Latin letter they look like. Keys
+// are case folded, so only lowercase characters are needed.
+var confusables = map[rune]rune{
+	'0': 'o', '1': 'l',
+
+	// Cyrillic
+	'а': 'a', 'в': 'b', 'е': 'e', 'һ': 'h', 'і': 'i', 'ј': 'j', 'к': 'k',
+	'ӏ': 'l', 'м': 'm', 'н': 'h', 'о': 'o', 'р': 'p', 'ԛ': 'q', 'с': 'c',
+	'ѕ': 's', 'т': 't', 'у': 'y', 'ԝ': 'w', 'х': 'x', 'ԁ': 'd',
+
+	// Greek
+	'α': 'a', 'β': 'b', 'ε': 'e', 'η': 'n', 'ι': 'i', 'κ': 'k', 'ν': 'v',
+	'ο': 'o', 'ρ': 'p', 'τ': 't', 'υ': 'u', 'χ': 'x',
+}
+
+// Key is what a name is compared by: its look-alike characters, case,
+// accents, compatibility forms (e.g. fullwidth letters) and separators are
+// all folded away, and "rn" reads as "m".
+func Key(name string) string {
+	return strings.ReplaceAll(fold(name), "rn", "m")
+}
+
+// fold folds a name character by character. Blocklisted words are matched
+// on it rather than on the Key, as multi-letter look-alikes would make them
+// match innocent names ("darn" in "adam").
+func fold(name string) string {
+	folded := norm.NFD.String(cases.Fold().String(norm.NFKC.String(name)))
+
+	var b strings.Builder
+
+	for _, r := range folded {
+		if unicode.Is(unicode.Mn, r) || strings.ContainsRune(Separators, r) {
+			continue
+		}
+
+		if latin, ok := confusables[r]; ok {
+			r = latin
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// Policy checks names against the rules of a config.
+type Policy struct {
+	minLength int
+	maxLength int
+	blocklist []string // Blocklisted words, folded
+}
+
+func NewPolicy(conf config.UsernameConfig) *Policy {
+	policy := &Policy{
+		minLength: conf.MinLength,
+		maxLength: conf.MaxLength,
+	}
+
+	for _, word := range conf.Blocklist {
+		if folded := fold(word); folded != "" {
+			policy.blocklist = append(policy.blocklist, folded)
+		}
+	}
+
+	return policy
+}
+
+// Check returns the name as it should be shown (trimmed and NFC
+// normalized), or why it is rejected.
+func (policy *Policy) Check(name string) (string, error) {
+	// Don't bother normalizing enormous names
+	if len(name) > policy.maxLength*utf8.UTFMax {
+		return "", policy.tooLong()
+	}
+
+	if !utf8.ValidString(name) {
+		return "", fmt.Errorf("%w: not valid UTF-8", ErrInvalidCharacters)
+	}
+
+	name = norm.NFC.String(strings.TrimSpace(name))
+	length := utf8.RuneCountInString(name)
+
+	if length < policy.minLength {
+		return "", fmt.Errorf("%w, must be at least %d characters", ErrTooShort, policy.minLength)
+	}
+
+	if length > policy.maxLength {
+		return "", policy.tooLong()
+	}
+
+	for i, r := range name {
+		letterOrDigit := unicode.IsLetter(r) || unicode.IsDigit(r)
+
+		if i == 0 && !letterOrDigit {
+			return "", fmt.Errorf("%w: must start with a letter or digit", ErrInvalidCharacters)
+		}
+
+		if !letterOrDigit && !unicode.Is(unicode.Mn, r) && !strings.ContainsRune(Separators, r) {
+			return "", fmt.Errorf("%w: %q", ErrInvalidCharacters, r)
+		}
+	}
+
+	if reserved[Key(name)] {
+		return "", ErrReserved
+	}
+
+	folded := fold(name)
+
+	for _, word := range policy.blocklist {
+		if strings.Contains(folded, word) {
+			return "", ErrBlocked
+		}
+	}
+
+	return name, nil
+}
+
+func (policy *Policy) tooLong() error {
+	return fmt.Errorf("%w, must be at most %d characters", ErrTooLong, policy.maxLength)
+}
@@ -0,0 +1,95 @@
+package username
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/maria-mz/bash-battle-server/config"
+	"github.com/maria-mz/bash-battle-server/log"
+	"github.com/stretchr/testify/assert"
+)
+
+var testConfig = config.UsernameConfig{
+	MinLength: 3,
+	MaxLength: 16,
+	Blocklist: []string{"darn"},
+}
+
+func TestMain(m *testing.M) {
+	log.InitLogger()
+	m.Run()
+}
+
+func TestCheck_Accepted(t *testing.T) {
+	policy := NewPolicy(testConfig)
+
+	tests := map[string]string{
+		"player-1":         "player-1",
+		"  alice  ":        "alice",
+		"José":             "José",
+		"Jose\u0301":       "José", // Combining accent, composed
+		"mei.li_2024":      "mei.li_2024",
+		"Ålesund":          "Ålesund",
+		"игрок":            "игрок",
+		"sixteen-chars-ok": "sixteen-chars-ok",
+		"adam":             "adam", // Not "darn", though "rn" looks like "m"
+		"Amsterdam":        "Amsterdam",
+	}
+
+	for input, expected := range tests {
+		name, err := policy.Check(input)
+
+		assert.Nil(t, err, input)
+		assert.Equal(t, expected, name, input)
+	}
+}
+
+func TestCheck_Rejected(t *testing.T) {
+	policy := NewPolicy(testConfig)
+
+	tests := map[string]error{
+		"":                       ErrTooShort,
+		"   ":                    ErrTooShort,
+		"ab":                     ErrTooShort,
+		"abcdefghijklmnopq":      ErrTooLong,
+		strings.Repeat("a", 1e6): ErrTooLong,
+		"two words":              ErrInvalidCharacters,
+		"tab\tname":              ErrInvalidCharacters,
+		"bell\x07":               ErrInvalidCharacters,
+		"\xffbad":                ErrInvalidCharacters,
+		"_leading":               ErrInvalidCharacters,
+		"emoji😀":                 ErrInvalidCharacters,
+		"zero\u200bwidth":        ErrInvalidCharacters,
+		"admin":                  ErrReserved,
+		"ADMIN":                  ErrReserved,
+		"Server":                 ErrReserved,
+		"r00t":                   ErrReserved,
+		"аdmin":                  ErrReserved, // Cyrillic а
+		"ＡＤＭＩＮ":                  ErrReserved, // Fullwidth
+		"sys_tem":                ErrReserved,
+		"darnit":                 ErrBlocked,
+		"oh-DARN":                ErrBlocked,
+		"da.rn":                  ErrBlocked,
+	}
+
+	for input, expected := range tests {
+		_, err := policy.Check(input)
+		assert.ErrorIs(t, err, expected, input)
+	}
+}
+
+func TestKey_LookAlikes(t *testing.T) {
+	lookAlikes := [][]string{
+		{"alice", "Alice", "ALICE", "аlice", "a.l.i.c.e", "Alíce", "ａｌｉｃｅ"},
+		{"bob1", "Bob_l", "B0B1"},
+		{"modern", "rnodern"},
+	}
+
+	for _, names := range lookAlikes {
+		for _, name := range names[1:] {
+			assert.Equal(t, Key(names[0]), Key(name), name)
+		}
+	}
+
+	assert.NotEqual(t, Key("alice"), Key("alicia"))
+}
@@ -16,6 +16,12 @@ func randomString(length int, charset string) string {
 	return string(b)
 }
 
-func GenerateToken() string {
+const gameIDCharset = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // No look-alikes (O/0, I/1)
+
+func GenerateSessionID() string {
 	return uuid.New().String()
 }
+
+func GenerateGameID() string {
+	return randomString(6, gameIDCharset)
+}